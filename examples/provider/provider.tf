@@ -15,3 +15,42 @@ provider "docker" {}
 #   tls_verify = true
 #   cert_path  = "~/.docker/certs"
 # }
+
+# Remote Docker over SSH
+# provider "docker" {
+#   host     = "ssh://deploy@docker-host"
+#   ssh_opts = ["-o", "Port=2222", "-i", "~/.ssh/deploy"]
+# }
+
+# Docker CLI context
+# provider "docker" {
+#   context = "staging"
+# }
+
+# Several hosts from one provider, selected per resource with docker_host
+# provider "docker" {
+#   hosts = {
+#     edge-1 = { host = "ssh://deploy@edge-1" }
+#     edge-2 = { host = "ssh://deploy@edge-2" }
+#   }
+# }
+#
+# resource "docker_container" "agent" {
+#   for_each    = toset(["edge-1", "edge-2"])
+#   docker_host = each.key
+#   name        = "agent"
+#   image       = "myorg/agent:latest"
+# }
+
+# Registry credentials shared by every pull, build, push and service.
+# Registries not listed here fall back to ~/.docker/config.json, including
+# credsStore and credHelpers such as docker-credential-ecr-login.
+# provider "docker" {
+#   config_file = "/ci/docker/config.json"
+#
+#   registry_auth {
+#     address  = "ghcr.io"
+#     username = var.ghcr_username
+#     password = var.ghcr_token
+#   }
+# }
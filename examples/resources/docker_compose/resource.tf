@@ -25,15 +25,22 @@ resource "docker_compose" "inline" {
   YAML
 }
 
-# Docker Compose with build and profiles
+# Docker Compose with override files, interpolation and profiles
 resource "docker_compose" "full" {
-  project_name   = "full-app"
-  compose_file   = "${path.module}/docker-compose.yml"
-  env_file       = "${path.module}/.env"
+  project_name = "full-app"
+  compose_files = [
+    "${path.module}/docker-compose.yml",
+    "${path.module}/docker-compose.override.yml",
+  ]
+  env_file = "${path.module}/.env"
+  environment = {
+    APP_VERSION = "1.2.3"
+  }
+  scale = {
+    worker = 3
+  }
   profiles       = ["development"]
-  build          = true
   force_recreate = false
-  pull           = "missing"
   remove_orphans = true
 }
 
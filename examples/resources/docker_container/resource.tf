@@ -59,6 +59,28 @@ resource "docker_container" "app" {
     read_only      = false
   }
 
+  # Configuration files, copied in before the container starts
+  upload {
+    file    = "/etc/nginx/conf.d/default.conf"
+    content = <<-EOT
+      server {
+        listen 80;
+        location / {
+          root /usr/share/nginx/html;
+        }
+      }
+    EOT
+  }
+
+  upload {
+    file       = "/docker-entrypoint.d/40-setup.sh"
+    executable = true
+    content    = <<-EOT
+      #!/bin/sh
+      echo "Hello from Terraform" > /usr/share/nginx/html/index.html
+    EOT
+  }
+
   # Network attachment
   networks = [docker_network.app.name]
 
@@ -76,4 +98,45 @@ resource "docker_container" "app" {
     timeout  = "10s"
     retries  = 3
   }
+
+  # Don't finish creating the container until the health check passes
+  wait         = true
+  wait_timeout = "2m"
+}
+
+# Legacy service with a stable address and DNS aliases
+resource "docker_network" "legacy" {
+  name = "legacy-network"
+
+  ipam {
+    config {
+      subnet = "172.29.0.0/16"
+    }
+  }
+}
+
+resource "docker_container" "legacy" {
+  name  = "legacy-service"
+  image = docker_image.nginx.image_id
+
+  networks_advanced {
+    name         = docker_network.legacy.name
+    ipv4_address = "172.29.0.10"
+    aliases      = ["legacy", "legacy.internal"]
+  }
+
+  networks_advanced {
+    name = docker_network.app.name
+  }
+}
+
+# One-shot job that must succeed before the apply continues
+resource "docker_container" "migrate" {
+  name    = "app-migrate"
+  image   = docker_image.nginx.image_id
+  command = ["sh", "-c", "echo running migrations"]
+
+  run_to_completion  = true
+  completion_timeout = "10m"
+  allowed_exit_codes = [0]
 }
@@ -20,6 +20,18 @@ resource "docker_image" "private" {
   }
 }
 
+# Build an image locally from a Dockerfile
+resource "docker_image" "app" {
+  name = "myapp:local"
+
+  build {
+    context = "${path.module}/app"
+    build_args = {
+      VERSION = "1.0.0"
+    }
+  }
+}
+
 variable "registry_username" {
   type      = string
   sensitive = true
@@ -0,0 +1,16 @@
+# Promote a release from the staging registry to production without pulling it locally
+resource "docker_registry_image_copy" "app" {
+  source_image = "staging.example.com/app:v1.2.0"
+  target_image = "prod.example.com/app:v1.2.0"
+}
+
+# Copy within one registry; layers are mounted instead of transferred
+resource "docker_registry_image_copy" "stable" {
+  source_image  = "registry.example.com/app@${var.release_digest}"
+  target_image  = "registry.example.com/app:stable"
+  keep_remotely = true
+}
+
+variable "release_digest" {
+  type = string
+}
@@ -0,0 +1,372 @@
+package distribution
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/registry"
+	"github.com/elioseverojunior/terraform-provider-docker/internal/docker"
+)
+
+const (
+	// DialTimeout, TLSHandshakeTimeout and ResponseHeaderTimeout bound each registry request up to its response
+	// headers. Bodies, which can be layers of several gigabytes, are only bounded by the caller's context.
+	DialTimeout           = 30 * time.Second
+	TLSHandshakeTimeout   = 10 * time.Second
+	ResponseHeaderTimeout = 2 * time.Minute
+
+	// dockerHubHost is the registry API endpoint of docker.io
+	dockerHubHost = "registry-1.docker.io"
+)
+
+// Client talks to container registries over the OCI Distribution API, without a Docker daemon.
+// Bearer tokens are cached per registry and scope, so one client should be shared by all resources.
+type Client struct {
+	httpClient *http.Client
+	registries *docker.AuthResolver
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// Error is an error response from a registry
+type Error struct {
+	StatusCode int
+	Status     string
+	Errors     []ErrorDetail `json:"errors"`
+}
+
+// ErrorDetail is one entry of a registry error response
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("registry error: %s", e.Status)
+	}
+	messages := make([]string, 0, len(e.Errors))
+	for _, detail := range e.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", detail.Code, detail.Message))
+	}
+	return fmt.Sprintf("registry error: %s - %s", e.Status, strings.Join(messages, "; "))
+}
+
+// IsStatus reports whether err is a registry error with the given HTTP status code
+func IsStatus(err error, statusCode int) bool {
+	var registryErr *Error
+	return errors.As(err, &registryErr) && registryErr.StatusCode == statusCode
+}
+
+// NewClient creates a registry client that takes credentials from the resolver
+func NewClient(registries *docker.AuthResolver) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Transport: newTransport(nil),
+		},
+		registries: registries,
+		tokens:     make(map[string]string),
+	}
+}
+
+// With returns a client that prefers the given credentials over those of c, for resources with their own
+// auth blocks. It shares the connections of c but caches tokens separately, because they are issued for
+// other credentials. Without credentials it returns c, so its token cache keeps being reused.
+func (c *Client) With(explicit []registry.AuthConfig) *Client {
+	if len(explicit) == 0 {
+		return c
+	}
+	return &Client{
+		httpClient: c.httpClient,
+		registries: c.registries.With(explicit),
+		tokens:     make(map[string]string),
+	}
+}
+
+// WithInsecureSkipVerify returns a client that does not verify registry TLS certificates. It shares the
+// credentials of c but keeps its own token cache.
+func (c *Client) WithInsecureSkipVerify() *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Transport: newTransport(&tls.Config{InsecureSkipVerify: true}),
+		},
+		registries: c.registries,
+		tokens:     make(map[string]string),
+	}
+}
+
+// newTransport creates the transport of a registry client, with timeouts on connecting and on waiting for
+// response headers but none on reading bodies
+func newTransport(tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   TLSHandshakeTimeout,
+		ResponseHeaderTimeout: ResponseHeaderTimeout,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// endpoint returns the base URL of a registry's API. Loopback registries are spoken to over plain HTTP,
+// the way the Docker daemon treats them as insecure by default.
+func endpoint(registry string) string {
+	if registry == "docker.io" {
+		return "https://" + dockerHubHost
+	}
+
+	host := registry
+	if h, _, err := net.SplitHostPort(registry); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return "http://" + registry
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return "http://" + registry
+	}
+	return "https://" + registry
+}
+
+// do sends the request built by newRequest, answering an authentication challenge and retrying once.
+// newRequest is called again for the retry, so request bodies must be replayable.
+func (c *Client) do(ctx context.Context, registry string, scopes []string, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	cacheKey := tokenCacheKey(registry, scopes)
+
+	req, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doOnce(ctx, registry, scopes, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	authorization, err := c.authorize(ctx, registry, challenge, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.tokens[cacheKey] = authorization
+	c.mu.Unlock()
+
+	req, err = newRequest()
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", authorization)
+
+	return c.httpClient.Do(req)
+}
+
+// doOnce sends req with the cached authorization for the registry and scopes, without answering a challenge.
+// It is for requests whose body can't be replayed, sent after another request has obtained the token.
+func (c *Client) doOnce(ctx context.Context, registry string, scopes []string, req *http.Request) (*http.Response, error) {
+	req = req.WithContext(ctx)
+
+	c.mu.Lock()
+	authorization := c.tokens[tokenCacheKey(registry, scopes)]
+	c.mu.Unlock()
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// tokenCacheKey is the key a token for a registry and scopes is cached under
+func tokenCacheKey(registry string, scopes []string) string {
+	return registry + " " + strings.Join(scopes, " ")
+}
+
+// authorize answers a WWW-Authenticate challenge with the registry's credentials and returns the
+// Authorization header value to use
+func (c *Client) authorize(ctx context.Context, registry, challenge string, scopes []string) (string, error) {
+	auth, err := c.registries.Resolve(registry)
+	if err != nil {
+		return "", err
+	}
+
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if auth.Username == "" {
+			return "", fmt.Errorf("registry %s requires credentials", registry)
+		}
+		req := &http.Request{Header: make(http.Header)}
+		req.SetBasicAuth(auth.Username, auth.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("registry %s returned unsupported authentication challenge %q", registry, challenge)
+	}
+
+	if auth.RegistryToken != "" {
+		return "Bearer " + auth.RegistryToken, nil
+	}
+
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry %s returned a bearer challenge without a realm", registry)
+	}
+
+	allScopes := scopes
+	if scope := params["scope"]; scope != "" && !containsString(scopes, scope) {
+		allScopes = append([]string{scope}, scopes...)
+	}
+
+	var req *http.Request
+	if auth.IdentityToken != "" {
+		// Identity tokens are OAuth2 refresh tokens and are exchanged with a POST
+		form := url.Values{}
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", auth.IdentityToken)
+		form.Set("service", params["service"])
+		form.Set("scope", strings.Join(allScopes, " "))
+		form.Set("client_id", "terraform-provider-docker")
+
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, realm, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		tokenURL, err := url.Parse(realm)
+		if err != nil {
+			return "", fmt.Errorf("invalid token realm %q: %w", realm, err)
+		}
+		query := tokenURL.Query()
+		if service := params["service"]; service != "" {
+			query.Set("service", service)
+		}
+		for _, scope := range allScopes {
+			query.Add("scope", scope)
+		}
+		tokenURL.RawQuery = query.Encode()
+
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if auth.Username != "" {
+			req.SetBasicAuth(auth.Username, auth.Password)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch registry token for %s: %w", registry, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("failed to fetch registry token for %s: %s - %s", registry, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode registry token for %s: %w", registry, err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return "", fmt.Errorf("registry %s returned an empty token", registry)
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallenge splits a WWW-Authenticate header into its scheme and parameters
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := make(map[string]string)
+
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = strings.TrimPrefix(strings.TrimSpace(value[end+2:]), ",")
+		} else {
+			value, rest, _ = strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(value)
+		}
+	}
+
+	return scheme, params
+}
+
+// checkResponse turns an error status into an *Error and closes the body
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	registryErr := &Error{StatusCode: resp.StatusCode, Status: resp.Status}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	_ = json.Unmarshal(body, registryErr)
+	return registryErr
+}
+
+// pullScope, pushScope and deleteScope are the token scopes for reading, writing and deleting in a repository
+func pullScope(repository string) string {
+	return fmt.Sprintf("repository:%s:pull", repository)
+}
+
+func pushScope(repository string) string {
+	return fmt.Sprintf("repository:%s:pull,push", repository)
+}
+
+func deleteScope(repository string) string {
+	return fmt.Sprintf("repository:%s:delete", repository)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// MediaTypeDockerForeignLayer is a Windows base layer that registries don't store and copies skip
+const MediaTypeDockerForeignLayer = "application/vnd.docker.image.rootfs.foreign.diff.tar.gzip"
+
+// CopyProgress is called after each blob or manifest is copied
+type CopyProgress func(desc ocispec.Descriptor, mounted bool)
+
+// Copy copies the manifest or image index src points at, with every manifest and blob it references,
+// to dst without going through a Docker daemon. Blobs that already exist in dst are skipped, and blobs
+// are mounted from the source repository instead of transferred when both are on the same registry.
+// It returns the descriptor of the manifest written to dst.
+func (c *Client) Copy(ctx context.Context, src, dst Reference, progress CopyProgress) (ocispec.Descriptor, error) {
+	if progress == nil {
+		progress = func(ocispec.Descriptor, bool) {}
+	}
+
+	dstScopes := []string{pushScope(dst.Repository)}
+	if src.Registry == dst.Registry && src.Repository != dst.Repository {
+		dstScopes = append(dstScopes, pullScope(src.Repository))
+	}
+
+	return c.copyManifest(ctx, src, dst, dst.Identifier(), dstScopes, progress)
+}
+
+// copyManifest copies one manifest and its references, then writes it to dst under identifier
+func (c *Client) copyManifest(ctx context.Context, src, dst Reference, identifier string, dstScopes []string, progress CopyProgress) (ocispec.Descriptor, error) {
+	desc, content, err := c.GetManifest(ctx, src)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	if IsIndex(desc.MediaType) {
+		var index ocispec.Index
+		if err := json.Unmarshal(content, &index); err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("failed to parse image index %s: %w", src, err)
+		}
+
+		for _, child := range index.Manifests {
+			childSrc := src
+			childSrc.Tag = ""
+			childSrc.Digest = child.Digest.String()
+
+			if _, err := c.copyManifest(ctx, childSrc, dst, child.Digest.String(), dstScopes, progress); err != nil {
+				return ocispec.Descriptor{}, err
+			}
+		}
+	} else {
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("failed to parse manifest %s: %w", src, err)
+		}
+		if manifest.Config.Digest == "" {
+			return ocispec.Descriptor{}, fmt.Errorf("manifest %s has unsupported media type %s", src, desc.MediaType)
+		}
+
+		blobs := append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...)
+		for _, blob := range blobs {
+			if blob.MediaType == MediaTypeDockerForeignLayer || len(blob.URLs) > 0 {
+				continue
+			}
+
+			mounted, err := c.copyBlob(ctx, src, dst, blob, dstScopes)
+			if err != nil {
+				return ocispec.Descriptor{}, err
+			}
+			progress(blob, mounted)
+		}
+	}
+
+	if err := c.PutManifest(ctx, dst, identifier, desc.MediaType, content, dstScopes); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	progress(desc, false)
+
+	return desc, nil
+}
+
+// copyBlob makes a blob of src available in dst and reports whether it was mounted rather than uploaded
+func (c *Client) copyBlob(ctx context.Context, src, dst Reference, blob ocispec.Descriptor, dstScopes []string) (bool, error) {
+	exists, err := c.blobExists(ctx, dst, blob.Digest, dstScopes)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	// Ask the registry to mount the blob from the source repository. It answers 201 when it did, or opens
+	// an ordinary upload session with 202 when it can't, for example because the source is not readable.
+	query := url.Values{}
+	if src.Registry == dst.Registry && src.Repository != dst.Repository {
+		query.Set("mount", blob.Digest.String())
+		query.Set("from", src.Repository)
+	}
+	uploadURL := fmt.Sprintf("%s/v2/%s/blobs/uploads/", endpoint(dst.Registry), dst.Repository)
+	if len(query) > 0 {
+		uploadURL += "?" + query.Encode()
+	}
+
+	resp, err := c.do(ctx, dst.Registry, dstScopes, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodPost, uploadURL, nil)
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return false, fmt.Errorf("failed to start upload of blob %s to %s: %w", blob.Digest, dst.Name(), err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusCreated {
+		return true, nil
+	}
+
+	location, err := resp.Location()
+	if err != nil {
+		return false, fmt.Errorf("registry %s returned no upload location for blob %s: %w", dst.Registry, blob.Digest, err)
+	}
+
+	// Stream the blob from the source straight into the upload
+	srcResp, err := c.do(ctx, src.Registry, []string{pullScope(src.Repository)}, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/%s/blobs/%s", endpoint(src.Registry), src.Repository, blob.Digest), nil)
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := checkResponse(srcResp); err != nil {
+		return false, fmt.Errorf("failed to read blob %s from %s: %w", blob.Digest, src.Name(), err)
+	}
+	defer srcResp.Body.Close()
+
+	uploadQuery := location.Query()
+	uploadQuery.Set("digest", blob.Digest.String())
+	location.RawQuery = uploadQuery.Encode()
+
+	// The streamed body can't be replayed, so the PUT reuses the token of the POST above and a challenge,
+	// for example because that token expired in between, fails the upload instead of being retried
+	req, err := http.NewRequest(http.MethodPut, location.String(), srcResp.Body)
+	if err != nil {
+		return false, err
+	}
+	req.ContentLength = blob.Size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err = c.doOnce(ctx, dst.Registry, dstScopes, req)
+	if err != nil {
+		return false, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return false, fmt.Errorf("failed to upload blob %s to %s: %w", blob.Digest, dst.Name(), err)
+	}
+	resp.Body.Close()
+
+	return false, nil
+}
+
+// blobExists reports whether a repository already holds a blob
+func (c *Client) blobExists(ctx context.Context, ref Reference, blobDigest digest.Digest, scopes []string) (bool, error) {
+	resp, err := c.do(ctx, ref.Registry, scopes, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodHead, fmt.Sprintf("%s/v2/%s/blobs/%s", endpoint(ref.Registry), ref.Repository, blobDigest), nil)
+	})
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return false, nil
+	}
+	if err := checkResponse(resp); err != nil {
+		return false, fmt.Errorf("failed to check blob %s in %s: %w", blobDigest, ref.Name(), err)
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// PutManifest writes a manifest to a repository under a tag or digest
+func (c *Client) PutManifest(ctx context.Context, ref Reference, identifier, mediaType string, content []byte, scopes []string) error {
+	if len(scopes) == 0 {
+		scopes = []string{pushScope(ref.Repository)}
+	}
+
+	resp, err := c.do(ctx, ref.Registry, scopes, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, manifestURL(ref, identifier), bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", mediaType)
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("failed to write manifest %s:%s: %w", ref.Name(), identifier, err)
+	}
+	resp.Body.Close()
+	return nil
+}
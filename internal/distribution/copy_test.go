@@ -0,0 +1,524 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/elioseverojunior/terraform-provider-docker/internal/docker"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// testRegistry is an in-memory stand-in for registry:2 that implements the parts of the Distribution API
+// the client uses. With token set, every request needs that bearer token, issued by /token.
+type testRegistry struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	token     string
+	manifests map[string]testManifest
+	blobs     map[string][]byte
+	uploads   int
+	requests  map[string]int
+
+	// afterUploadStart runs when an upload session was opened, before the response is sent
+	afterUploadStart func()
+}
+
+type testManifest struct {
+	mediaType string
+	content   []byte
+}
+
+func newTestRegistry(t *testing.T) *testRegistry {
+	t.Helper()
+
+	r := &testRegistry{
+		manifests: make(map[string]testManifest),
+		blobs:     make(map[string][]byte),
+		requests:  make(map[string]int),
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+// host is the registry name used in references, such as 127.0.0.1:12345
+func (r *testRegistry) host() string {
+	return strings.TrimPrefix(r.server.URL, "http://")
+}
+
+func (r *testRegistry) count(method, kind string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests[method+" "+kind]
+}
+
+func (r *testRegistry) putBlob(repository string, content []byte) ocispec.Descriptor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayerGzip,
+		Digest:    digest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+	r.blobs[repository+"@"+desc.Digest.String()] = content
+	return desc
+}
+
+func (r *testRegistry) putManifest(repository, tag, mediaType string, content []byte) ocispec.Descriptor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+	r.manifests[repository+"@"+desc.Digest.String()] = testManifest{mediaType: mediaType, content: content}
+	if tag != "" {
+		r.manifests[repository+":"+tag] = testManifest{mediaType: mediaType, content: content}
+	}
+	return desc
+}
+
+// putImage stores a config and one layer and a manifest referencing them
+func (r *testRegistry) putImage(t *testing.T, repository, tag, layer string) ocispec.Descriptor {
+	t.Helper()
+
+	config := r.putBlob(repository, []byte(`{"architecture":"amd64","os":"linux"}`))
+	config.MediaType = ocispec.MediaTypeImageConfig
+	manifest := ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    config,
+		Layers:    []ocispec.Descriptor{r.putBlob(repository, []byte(layer))},
+	}
+	manifest.SchemaVersion = 2
+
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r.putManifest(repository, tag, ocispec.MediaTypeImageManifest, content)
+}
+
+func (r *testRegistry) hasBlob(repository string, blobDigest digest.Digest) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.blobs[repository+"@"+blobDigest.String()]
+	return ok
+}
+
+func (r *testRegistry) manifest(repository, identifier string) (testManifest, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sep := ":"
+	if strings.HasPrefix(identifier, "sha256:") {
+		sep = "@"
+	}
+	m, ok := r.manifests[repository+sep+identifier]
+	return m, ok
+}
+
+func (r *testRegistry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		r.mu.Lock()
+		token := r.token
+		r.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": token})
+		return
+	}
+
+	r.mu.Lock()
+	token := r.token
+	r.mu.Unlock()
+	if token != "" && req.Header.Get("Authorization") != "Bearer "+token {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, r.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	switch {
+	case strings.Contains(path, "/manifests/"):
+		repository, identifier, _ := strings.Cut(path, "/manifests/")
+		r.record(req.Method, "manifest")
+		r.serveManifest(w, req, repository, identifier)
+	case strings.Contains(path, "/blobs/uploads/"):
+		repository, _, _ := strings.Cut(path, "/blobs/uploads/")
+		r.record(req.Method, "upload")
+		r.serveUpload(w, req, repository)
+	case strings.Contains(path, "/blobs/"):
+		repository, blobDigest, _ := strings.Cut(path, "/blobs/")
+		r.record(req.Method, "blob")
+		r.serveBlob(w, req, repository, blobDigest)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (r *testRegistry) record(method, kind string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests[method+" "+kind]++
+}
+
+func (r *testRegistry) serveManifest(w http.ResponseWriter, req *http.Request, repository, identifier string) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		m, ok := r.manifest(repository, identifier)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", m.mediaType)
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(m.content).String())
+		w.Header().Set("Content-Length", fmt.Sprint(len(m.content)))
+		if req.Method == http.MethodGet {
+			_, _ = w.Write(m.content)
+		}
+	case http.MethodPut:
+		content, _ := io.ReadAll(req.Body)
+		tag := identifier
+		if strings.HasPrefix(identifier, "sha256:") {
+			tag = ""
+		}
+		desc := r.putManifest(repository, tag, req.Header.Get("Content-Type"), content)
+		w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (r *testRegistry) serveBlob(w http.ResponseWriter, req *http.Request, repository, blobDigest string) {
+	r.mu.Lock()
+	content, ok := r.blobs[repository+"@"+blobDigest]
+	r.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+	if req.Method == http.MethodGet {
+		_, _ = w.Write(content)
+	}
+}
+
+func (r *testRegistry) serveUpload(w http.ResponseWriter, req *http.Request, repository string) {
+	switch req.Method {
+	case http.MethodPost:
+		query := req.URL.Query()
+		if mount, from := query.Get("mount"), query.Get("from"); mount != "" {
+			r.mu.Lock()
+			content, ok := r.blobs[from+"@"+mount]
+			if ok {
+				r.blobs[repository+"@"+mount] = content
+			}
+			r.mu.Unlock()
+			if ok {
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+		}
+
+		r.mu.Lock()
+		r.uploads++
+		id := r.uploads
+		r.mu.Unlock()
+		if r.afterUploadStart != nil {
+			r.afterUploadStart()
+		}
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%d", repository, id))
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodPut:
+		content, _ := io.ReadAll(req.Body)
+		blobDigest := req.URL.Query().Get("digest")
+		if digest.FromBytes(content).String() != blobDigest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.mu.Lock()
+		r.blobs[repository+"@"+blobDigest] = content
+		r.mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestClient() *Client {
+	return NewClient(docker.NewAuthResolver("", nil))
+}
+
+func mustParseReference(t *testing.T, name string) Reference {
+	t.Helper()
+
+	ref, err := ParseReference(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
+
+func TestCopyBetweenRegistries(t *testing.T) {
+	src := newTestRegistry(t)
+	dst := newTestRegistry(t)
+	dst.token = "push-token"
+
+	want := src.putImage(t, "app", "v1", "layer")
+
+	var copied []ocispec.Descriptor
+	desc, err := newTestClient().Copy(context.Background(),
+		mustParseReference(t, src.host()+"/app:v1"),
+		mustParseReference(t, dst.host()+"/team/app:v1"),
+		func(desc ocispec.Descriptor, mounted bool) {
+			if mounted {
+				t.Errorf("blob %s was mounted across registries", desc.Digest)
+			}
+			copied = append(copied, desc)
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if desc.Digest != want.Digest {
+		t.Errorf("Copy returned digest %s, want %s", desc.Digest, want.Digest)
+	}
+	if len(copied) != 3 {
+		t.Errorf("progress reported %d descriptors, want config, layer and manifest", len(copied))
+	}
+	if m, ok := dst.manifest("team/app", "v1"); !ok || digest.FromBytes(m.content) != want.Digest {
+		t.Errorf("target tag does not hold the copied manifest")
+	}
+	if !dst.hasBlob("team/app", digest.FromBytes([]byte("layer"))) {
+		t.Errorf("layer was not uploaded to the target")
+	}
+}
+
+func TestCopySkipsExistingBlobs(t *testing.T) {
+	src := newTestRegistry(t)
+	dst := newTestRegistry(t)
+
+	src.putImage(t, "app", "v1", "layer")
+	dst.putImage(t, "app", "old", "layer")
+
+	_, err := newTestClient().Copy(context.Background(),
+		mustParseReference(t, src.host()+"/app:v1"),
+		mustParseReference(t, dst.host()+"/app:v1"),
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := dst.count(http.MethodPost, "upload"); n != 0 {
+		t.Errorf("started %d uploads for blobs the target already has", n)
+	}
+}
+
+func TestCopyMountsWithinRegistry(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.putImage(t, "staging/app", "v1", "layer")
+
+	mounts := 0
+	_, err := newTestClient().Copy(context.Background(),
+		mustParseReference(t, registry.host()+"/staging/app:v1"),
+		mustParseReference(t, registry.host()+"/prod/app:v1"),
+		func(desc ocispec.Descriptor, mounted bool) {
+			if mounted {
+				mounts++
+			}
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mounts != 2 {
+		t.Errorf("mounted %d blobs, want config and layer", mounts)
+	}
+	if n := registry.count(http.MethodGet, "blob"); n != 0 {
+		t.Errorf("downloaded %d blobs that could be mounted", n)
+	}
+	if _, ok := registry.manifest("prod/app", "v1"); !ok {
+		t.Errorf("target tag was not written")
+	}
+}
+
+func TestCopyIndex(t *testing.T) {
+	src := newTestRegistry(t)
+	dst := newTestRegistry(t)
+
+	amd64 := src.putImage(t, "app", "", "amd64 layer")
+	amd64.Platform = &ocispec.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := src.putImage(t, "app", "", "arm64 layer")
+	arm64.Platform = &ocispec.Platform{OS: "linux", Architecture: "arm64"}
+
+	index := ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{amd64, arm64}}
+	index.SchemaVersion = 2
+	content, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := src.putManifest("app", "v1", ocispec.MediaTypeImageIndex, content)
+
+	desc, err := newTestClient().Copy(context.Background(),
+		mustParseReference(t, src.host()+"/app:v1"),
+		mustParseReference(t, dst.host()+"/app:v1"),
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if desc.Digest != want.Digest || desc.MediaType != ocispec.MediaTypeImageIndex {
+		t.Errorf("Copy returned %s %s, want the index %s", desc.MediaType, desc.Digest, want.Digest)
+	}
+	for _, child := range index.Manifests {
+		if _, ok := dst.manifest("app", child.Digest.String()); !ok {
+			t.Errorf("platform manifest %s was not copied", child.Digest)
+		}
+	}
+	if !dst.hasBlob("app", digest.FromBytes([]byte("arm64 layer"))) {
+		t.Errorf("layer of the second platform was not copied")
+	}
+}
+
+func TestCopyDoesNotReplayBlobUpload(t *testing.T) {
+	src := newTestRegistry(t)
+	dst := newTestRegistry(t)
+	dst.token = "first-token"
+
+	src.putImage(t, "app", "v1", "layer")
+
+	// Expire the token between opening the upload session and sending the blob
+	dst.afterUploadStart = func() {
+		dst.mu.Lock()
+		dst.token = "second-token"
+		dst.mu.Unlock()
+	}
+
+	_, err := newTestClient().Copy(context.Background(),
+		mustParseReference(t, src.host()+"/app:v1"),
+		mustParseReference(t, dst.host()+"/app:v1"),
+		nil,
+	)
+	if !IsStatus(err, http.StatusUnauthorized) {
+		t.Fatalf("Copy returned %v, want a 401 registry error", err)
+	}
+	if n := dst.count(http.MethodPut, "upload"); n != 0 {
+		t.Errorf("the rejected blob upload reached the handler %d times", n)
+	}
+}
+
+func TestHeadManifestNotFound(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	_, err := newTestClient().HeadManifest(context.Background(), mustParseReference(t, registry.host()+"/app:missing"))
+	if !IsStatus(err, http.StatusNotFound) {
+		t.Fatalf("HeadManifest returned %v, want a 404 registry error", err)
+	}
+}
+
+func TestPutManifestReplaysBody(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.token = "push-token"
+
+	content := []byte(`{"schemaVersion":2}`)
+	err := newTestClient().PutManifest(context.Background(), mustParseReference(t, registry.host()+"/app:v1"), "v1", ocispec.MediaTypeImageManifest, content, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, ok := registry.manifest("app", "v1")
+	if !ok || !bytes.Equal(m.content, content) {
+		t.Errorf("manifest written after the challenge is %q, want %q", m.content, content)
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		name string
+		want Reference
+	}{
+		{"nginx", Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}},
+		{"nginx:1.27", Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "1.27"}},
+		{"ghcr.io/org/app:v1", Reference{Registry: "ghcr.io", Repository: "org/app", Tag: "v1"}},
+		{"localhost:5000/app", Reference{Registry: "localhost:5000", Repository: "app", Tag: "latest"}},
+		{
+			"app@sha256:" + strings.Repeat("a", 64),
+			Reference{Registry: "docker.io", Repository: "library/app", Digest: "sha256:" + strings.Repeat("a", 64)},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseReference(tt.name)
+		if err != nil {
+			t.Errorf("ParseReference(%q) returned %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseReference(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := ParseReference("Invalid/Name"); err == nil {
+		t.Errorf("ParseReference accepted an upper case repository")
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	tests := []struct {
+		challenge  string
+		wantScheme string
+		wantParams map[string]string
+	}{
+		{
+			`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"`,
+			"Bearer",
+			map[string]string{"realm": "https://auth.docker.io/token", "service": "registry.docker.io", "scope": "repository:library/nginx:pull"},
+		},
+		{
+			`Basic realm="Registry Realm"`,
+			"Basic",
+			map[string]string{"realm": "Registry Realm"},
+		},
+		{
+			`Bearer realm=https://ghcr.io/token, service=ghcr.io`,
+			"Bearer",
+			map[string]string{"realm": "https://ghcr.io/token", "service": "ghcr.io"},
+		},
+		{
+			`Bearer realm="https://example.com/token",scope="repository:a:pull,push"`,
+			"Bearer",
+			map[string]string{"realm": "https://example.com/token", "scope": "repository:a:pull,push"},
+		},
+	}
+	for _, tt := range tests {
+		scheme, params := parseChallenge(tt.challenge)
+		if scheme != tt.wantScheme {
+			t.Errorf("parseChallenge(%q) scheme = %q, want %q", tt.challenge, scheme, tt.wantScheme)
+		}
+		if len(params) != len(tt.wantParams) {
+			t.Errorf("parseChallenge(%q) params = %v, want %v", tt.challenge, params, tt.wantParams)
+			continue
+		}
+		for key, want := range tt.wantParams {
+			if params[key] != want {
+				t.Errorf("parseChallenge(%q) %s = %q, want %q", tt.challenge, key, params[key], want)
+			}
+		}
+	}
+}
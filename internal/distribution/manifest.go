@@ -0,0 +1,295 @@
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	// maxManifestSize bounds manifest downloads, matching the limit of the registry implementation
+	maxManifestSize = 4 * 1024 * 1024
+)
+
+// manifestAccept lists the manifest media types the client understands, image indexes first
+var manifestAccept = strings.Join([]string{
+	ocispec.MediaTypeImageIndex,
+	MediaTypeDockerManifestList,
+	ocispec.MediaTypeImageManifest,
+	MediaTypeDockerManifest,
+}, ", ")
+
+// Reference is an image reference split into the parts the Distribution API addresses
+type Reference struct {
+	// Registry is the registry hostname, docker.io for Docker Hub
+	Registry string
+	// Repository is the repository path within the registry, library/nginx for official images
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses an image name such as nginx, ghcr.io/org/app:v1 or app@sha256:..., defaulting
+// the tag to latest when neither a tag nor a digest is given
+func ParseReference(name string) (Reference, error) {
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return Reference{}, fmt.Errorf("invalid image reference %s: %w", name, err)
+	}
+
+	ref := Reference{
+		Registry:   reference.Domain(named),
+		Repository: reference.Path(named),
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		ref.Tag = tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		ref.Digest = digested.Digest().String()
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+// Identifier returns the digest when the reference has one, otherwise the tag
+func (r Reference) Identifier() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// Name returns the repository name including the registry, such as docker.io/library/nginx
+func (r Reference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+func (r Reference) String() string {
+	name := r.Name()
+	if r.Tag != "" {
+		name += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		name += "@" + r.Digest
+	}
+	return name
+}
+
+// IsIndex reports whether a media type is an OCI image index or a Docker manifest list
+func IsIndex(mediaType string) bool {
+	return mediaType == ocispec.MediaTypeImageIndex || mediaType == MediaTypeDockerManifestList
+}
+
+// manifestURL returns the URL of a manifest by tag or digest
+func manifestURL(ref Reference, identifier string) string {
+	return fmt.Sprintf("%s/v2/%s/manifests/%s", endpoint(ref.Registry), ref.Repository, identifier)
+}
+
+// HeadManifest returns the descriptor of the manifest a reference points at without downloading it.
+// Registries that omit Docker-Content-Digest on HEAD are asked with a GET instead.
+func (c *Client) HeadManifest(ctx context.Context, ref Reference) (ocispec.Descriptor, error) {
+	resp, err := c.do(ctx, ref.Registry, []string{pullScope(ref.Repository)}, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodHead, manifestURL(ref, ref.Identifier()), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", manifestAccept)
+		return req, nil
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to read manifest %s: %w", ref, err)
+	}
+	resp.Body.Close()
+
+	contentDigest := resp.Header.Get("Docker-Content-Digest")
+	if contentDigest == "" {
+		desc, _, err := c.GetManifest(ctx, ref)
+		return desc, err
+	}
+
+	size, _ := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	return descriptor(resp.Header.Get("Content-Type"), contentDigest, size)
+}
+
+// GetManifest downloads the manifest a reference points at and returns its descriptor and content
+func (c *Client) GetManifest(ctx context.Context, ref Reference) (ocispec.Descriptor, []byte, error) {
+	resp, err := c.do(ctx, ref.Registry, []string{pullScope(ref.Repository)}, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodGet, manifestURL(ref, ref.Identifier()), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", manifestAccept)
+		return req, nil
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to read manifest %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to read manifest %s: %w", ref, err)
+	}
+	if len(content) > maxManifestSize {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("manifest %s exceeds %d bytes", ref, maxManifestSize)
+	}
+
+	mediaType := resp.Header.Get("Content-Type")
+	if mediaType == "" || mediaType == "application/json" || mediaType == "text/plain" {
+		// Fall back to the mediaType field for registries that serve manifests with a generic type
+		var probe struct {
+			MediaType string `json:"mediaType"`
+		}
+		_ = json.Unmarshal(content, &probe)
+		mediaType = probe.MediaType
+	}
+
+	computed := digest.FromBytes(content).String()
+	if reported := resp.Header.Get("Docker-Content-Digest"); reported != "" && reported != computed {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("manifest %s has digest %s, registry reported %s", ref, computed, reported)
+	}
+	if ref.Digest != "" && ref.Digest != computed {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("manifest %s has digest %s", ref, computed)
+	}
+
+	desc, err := descriptor(mediaType, computed, int64(len(content)))
+	return desc, content, err
+}
+
+// GetIndex downloads an image index or manifest list
+func (c *Client) GetIndex(ctx context.Context, ref Reference) (ocispec.Descriptor, *ocispec.Index, error) {
+	desc, content, err := c.GetManifest(ctx, ref)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	if !IsIndex(desc.MediaType) {
+		return desc, nil, fmt.Errorf("manifest %s is a %s, not an image index", ref, desc.MediaType)
+	}
+
+	var index ocispec.Index
+	if err := json.Unmarshal(content, &index); err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to parse image index %s: %w", ref, err)
+	}
+	return desc, &index, nil
+}
+
+// DeleteManifest deletes the manifest a reference points at. Tags are resolved to their digest first,
+// because the Distribution API only deletes by digest; every tag of that manifest is removed with it.
+// Registries without deletion enabled answer with 405 Method Not Allowed, see IsStatus.
+func (c *Client) DeleteManifest(ctx context.Context, ref Reference) error {
+	contentDigest := ref.Digest
+	if contentDigest == "" {
+		desc, err := c.HeadManifest(ctx, ref)
+		if err != nil {
+			return err
+		}
+		contentDigest = desc.Digest.String()
+	}
+
+	resp, err := c.do(ctx, ref.Registry, []string{deleteScope(ref.Repository)}, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodDelete, manifestURL(ref, contentDigest), nil)
+	})
+	if err != nil {
+		return err
+	}
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("failed to delete manifest %s@%s: %w", ref.Name(), contentDigest, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// descriptor builds a descriptor from response headers, dropping media type parameters
+func descriptor(mediaType, contentDigest string, size int64) (ocispec.Descriptor, error) {
+	parsed, err := digest.Parse(contentDigest)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("registry returned invalid digest %q: %w", contentDigest, err)
+	}
+
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	return ocispec.Descriptor{
+		MediaType: strings.TrimSpace(mediaType),
+		Digest:    parsed,
+		Size:      size,
+	}, nil
+}
+
+// ParsePlatform parses a platform such as linux/amd64 or linux/arm64/v8
+func ParsePlatform(platform string) (ocispec.Platform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return ocispec.Platform{}, fmt.Errorf("invalid platform %q, expected os/arch or os/arch/variant", platform)
+	}
+
+	spec := ocispec.Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		spec.Variant = parts[2]
+	}
+	return spec, nil
+}
+
+// FormatPlatform formats a platform as os/arch or os/arch/variant
+func FormatPlatform(platform ocispec.Platform) string {
+	formatted := platform.OS + "/" + platform.Architecture
+	if platform.Variant != "" {
+		formatted += "/" + platform.Variant
+	}
+	return formatted
+}
+
+// FindPlatform returns the manifest of an image index that matches a platform
+func FindPlatform(index *ocispec.Index, platform ocispec.Platform) (ocispec.Descriptor, bool) {
+	for _, manifest := range index.Manifests {
+		if manifest.Platform == nil {
+			continue
+		}
+		if manifest.Platform.OS == platform.OS && manifest.Platform.Architecture == platform.Architecture &&
+			(platform.Variant == "" || manifest.Platform.Variant == platform.Variant) {
+			return manifest, true
+		}
+	}
+	return ocispec.Descriptor{}, false
+}
+
+// PutIndex writes an OCI image index referencing manifests that already exist in the repository, and
+// returns its descriptor
+func (c *Client) PutIndex(ctx context.Context, ref Reference, manifests []ocispec.Descriptor) (ocispec.Descriptor, error) {
+	content, err := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: manifests,
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to encode image index: %w", err)
+	}
+
+	if err := c.PutManifest(ctx, ref, ref.Identifier(), ocispec.MediaTypeImageIndex, content, nil); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	return ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Digest:    digest.FromBytes(content),
+		Size:      int64(len(content)),
+	}, nil
+}
@@ -0,0 +1,376 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// IndexServer is the address docker CLI configuration uses for Docker Hub credentials
+const IndexServer = "https://index.docker.io/v1/"
+
+// credentialHelperTokenUsername is the username credential helpers return for identity tokens
+const credentialHelperTokenUsername = "<token>"
+
+// credentialHelperTimeout bounds a single credential helper run, so a helper waiting on a locked keychain
+// or an unreachable backend cannot hang the provider
+const credentialHelperTimeout = 30 * time.Second
+
+// AuthResolver resolves registry credentials from the provider configuration, the docker CLI config file
+// and the credential helpers (docker-credential-*) that config file names. Lookups are cached, so every
+// resource sharing a resolver runs each helper at most once per registry.
+type AuthResolver struct {
+	configFile string
+	explicit   map[string]registry.AuthConfig
+	parent     *AuthResolver
+
+	mu       sync.Mutex
+	loaded   bool
+	config   *dockerConfigFile
+	loadErr  error
+	resolved map[string]registry.AuthConfig
+}
+
+type dockerConfigFile struct {
+	Auths       map[string]dockerConfigAuth `json:"auths"`
+	CredsStore  string                      `json:"credsStore"`
+	CredHelpers map[string]string           `json:"credHelpers"`
+}
+
+type dockerConfigAuth struct {
+	Auth          string `json:"auth"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+	RegistryToken string `json:"registrytoken"`
+}
+
+type credentialHelperOutput struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// DefaultConfigFile returns the docker CLI config.json path, honoring DOCKER_CONFIG
+func DefaultConfigFile() string {
+	return filepath.Join(ConfigDir(), "config.json")
+}
+
+// NewAuthResolver creates a resolver. Explicit credentials take precedence over the config file, which
+// is read on first use. A config file that does not exist is treated as empty.
+func NewAuthResolver(configFile string, explicit []registry.AuthConfig) *AuthResolver {
+	return &AuthResolver{
+		configFile: configFile,
+		explicit:   indexAuthConfigs(explicit),
+		resolved:   make(map[string]registry.AuthConfig),
+	}
+}
+
+// With returns a resolver that prefers the given credentials and falls back to r.
+// Resources use it to layer their own auth blocks over the provider configuration.
+func (r *AuthResolver) With(explicit []registry.AuthConfig) *AuthResolver {
+	if len(explicit) == 0 {
+		return r
+	}
+	return &AuthResolver{
+		explicit: indexAuthConfigs(explicit),
+		parent:   r,
+		resolved: make(map[string]registry.AuthConfig),
+	}
+}
+
+// Resolve returns the credentials for a registry address, or empty credentials when none are configured
+func (r *AuthResolver) Resolve(address string) (registry.AuthConfig, error) {
+	if r == nil {
+		return registry.AuthConfig{}, nil
+	}
+
+	key := RegistryHostname(address)
+	if auth, ok := r.explicit[key]; ok {
+		return auth, nil
+	}
+	if r.parent != nil {
+		return r.parent.Resolve(address)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if auth, ok := r.resolved[key]; ok {
+		return auth, nil
+	}
+
+	config, err := r.loadConfig()
+	if err != nil {
+		return registry.AuthConfig{}, err
+	}
+
+	auth, err := config.resolve(key)
+	if err != nil {
+		return registry.AuthConfig{}, err
+	}
+	r.resolved[key] = auth
+	return auth, nil
+}
+
+// ResolveImage returns the credentials for the registry an image reference points at
+func (r *AuthResolver) ResolveImage(imageName string) (registry.AuthConfig, error) {
+	address, err := ImageRegistry(imageName)
+	if err != nil {
+		return registry.AuthConfig{}, err
+	}
+	return r.Resolve(address)
+}
+
+// EncodedImageAuth returns the base64 encoded X-Registry-Auth value for pulling or pushing an image.
+// It is empty when no credentials are configured for the image's registry.
+func (r *AuthResolver) EncodedImageAuth(imageName string) (string, error) {
+	auth, err := r.ResolveImage(imageName)
+	if err != nil {
+		return "", err
+	}
+	if auth == (registry.AuthConfig{}) {
+		return "", nil
+	}
+	return registry.EncodeAuthConfig(auth)
+}
+
+// AuthConfigs returns the credentials of every registry the resolver knows about, keyed by server address,
+// for image builds that may pull base images from any of them. Registries whose credentials cannot be
+// resolved are left out with a warning, the way the docker CLI does, since a build rarely needs all of them.
+func (r *AuthResolver) AuthConfigs(ctx context.Context) (map[string]registry.AuthConfig, error) {
+	byHostname, err := r.authConfigsByHostname(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	authConfigs := make(map[string]registry.AuthConfig, len(byHostname))
+	for _, auth := range byHostname {
+		authConfigs[auth.ServerAddress] = auth
+	}
+	return authConfigs, nil
+}
+
+// authConfigsByHostname returns the known credentials keyed by registry hostname, so that explicit
+// credentials replace config file entries that use another alias for the same registry
+func (r *AuthResolver) authConfigsByHostname(ctx context.Context) (map[string]registry.AuthConfig, error) {
+	if r == nil {
+		return make(map[string]registry.AuthConfig), nil
+	}
+
+	var authConfigs map[string]registry.AuthConfig
+	if r.parent != nil {
+		var err error
+		authConfigs, err = r.parent.authConfigsByHostname(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		r.mu.Lock()
+		config, err := r.loadConfig()
+		r.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		authConfigs = make(map[string]registry.AuthConfig)
+		for _, address := range config.addresses() {
+			auth, err := r.Resolve(address)
+			if err != nil {
+				tflog.Warn(ctx, "Skipping registry credentials that could not be resolved", map[string]interface{}{
+					"registry": address,
+					"error":    err.Error(),
+				})
+				continue
+			}
+			if auth != (registry.AuthConfig{}) {
+				authConfigs[RegistryHostname(address)] = auth
+			}
+		}
+	}
+
+	for hostname, auth := range r.explicit {
+		authConfigs[hostname] = auth
+	}
+	return authConfigs, nil
+}
+
+// loadConfig reads the config file once. The caller must hold r.mu.
+func (r *AuthResolver) loadConfig() (*dockerConfigFile, error) {
+	if r.loaded {
+		return r.config, r.loadErr
+	}
+	r.loaded = true
+	r.config = &dockerConfigFile{}
+
+	if r.configFile == "" {
+		return r.config, nil
+	}
+
+	content, err := os.ReadFile(r.configFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			r.loadErr = fmt.Errorf("failed to read docker config %s: %w", r.configFile, err)
+		}
+		return r.config, r.loadErr
+	}
+
+	if err := json.Unmarshal(content, r.config); err != nil {
+		r.loadErr = fmt.Errorf("failed to parse docker config %s: %w", r.configFile, err)
+	}
+	return r.config, r.loadErr
+}
+
+// resolve looks a registry up the way the docker CLI does: a per-registry credential helper wins over
+// the default credential store, which wins over credentials stored inline in auths
+func (c *dockerConfigFile) resolve(hostname string) (registry.AuthConfig, error) {
+	serverAddress := hostname
+	if hostname == RegistryHostname(IndexServer) {
+		serverAddress = IndexServer
+	}
+
+	for host, helper := range c.CredHelpers {
+		if RegistryHostname(host) == hostname && helper != "" {
+			return credentialHelperGet(helper, serverAddress)
+		}
+	}
+
+	if c.CredsStore != "" {
+		auth, err := credentialHelperGet(c.CredsStore, serverAddress)
+		if err != nil || auth != (registry.AuthConfig{}) {
+			return auth, err
+		}
+	}
+
+	for address, entry := range c.Auths {
+		if RegistryHostname(address) != hostname {
+			continue
+		}
+
+		auth := registry.AuthConfig{
+			ServerAddress: address,
+			Username:      entry.Username,
+			Password:      entry.Password,
+			IdentityToken: entry.IdentityToken,
+			RegistryToken: entry.RegistryToken,
+		}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return registry.AuthConfig{}, fmt.Errorf("invalid auth for %s in docker config: %w", address, err)
+			}
+			username, password, ok := strings.Cut(string(decoded), ":")
+			if !ok {
+				return registry.AuthConfig{}, fmt.Errorf("invalid auth for %s in docker config: expected username:password", address)
+			}
+			auth.Username = username
+			auth.Password = password
+		}
+		return auth, nil
+	}
+
+	return registry.AuthConfig{}, nil
+}
+
+// addresses returns every registry address named in auths or credHelpers
+func (c *dockerConfigFile) addresses() []string {
+	addresses := make([]string, 0, len(c.Auths)+len(c.CredHelpers))
+	for address := range c.Auths {
+		addresses = append(addresses, address)
+	}
+	for address := range c.CredHelpers {
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// credentialHelperGet runs docker-credential-<helper> get for a server address. A helper that has no
+// credentials for the address yields empty credentials rather than an error.
+func credentialHelperGet(helper, serverAddress string) (registry.AuthConfig, error) {
+	program := "docker-credential-" + helper
+
+	ctx, cancel := context.WithTimeout(context.Background(), credentialHelperTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, program, "get")
+	cmd.Stdin = strings.NewReader(serverAddress)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return registry.AuthConfig{}, fmt.Errorf("credential helper %s timed out after %s for %s", program, credentialHelperTimeout, serverAddress)
+		}
+		output := strings.TrimSpace(stdout.String() + stderr.String())
+		if strings.Contains(strings.ToLower(output), "credentials not found") {
+			return registry.AuthConfig{}, nil
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && output != "" {
+			return registry.AuthConfig{}, fmt.Errorf("credential helper %s failed for %s: %s", program, serverAddress, output)
+		}
+		return registry.AuthConfig{}, fmt.Errorf("credential helper %s failed for %s: %w", program, serverAddress, err)
+	}
+
+	var output credentialHelperOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return registry.AuthConfig{}, fmt.Errorf("credential helper %s returned invalid output: %w", program, err)
+	}
+
+	auth := registry.AuthConfig{ServerAddress: serverAddress}
+	if output.Username == credentialHelperTokenUsername {
+		auth.IdentityToken = output.Secret
+	} else {
+		auth.Username = output.Username
+		auth.Password = output.Secret
+	}
+	return auth, nil
+}
+
+// RegistryHostname reduces a registry address such as https://registry.example.com/v2/ to its hostname.
+// All Docker Hub aliases map to the hostname of IndexServer.
+func RegistryHostname(address string) string {
+	hostname := address
+	if _, rest, ok := strings.Cut(hostname, "://"); ok {
+		hostname = rest
+	}
+	hostname, _, _ = strings.Cut(hostname, "/")
+
+	switch hostname {
+	case "docker.io", "registry-1.docker.io", "index.docker.io":
+		return "index.docker.io"
+	}
+	return hostname
+}
+
+// ImageRegistry returns the registry hostname of an image reference, docker.io for unqualified names
+func ImageRegistry(imageName string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return "", fmt.Errorf("invalid image reference %s: %w", imageName, err)
+	}
+	return reference.Domain(named), nil
+}
+
+// indexAuthConfigs keys credentials by registry hostname
+func indexAuthConfigs(authConfigs []registry.AuthConfig) map[string]registry.AuthConfig {
+	indexed := make(map[string]registry.AuthConfig, len(authConfigs))
+	for _, auth := range authConfigs {
+		indexed[RegistryHostname(auth.ServerAddress)] = auth
+	}
+	return indexed
+}
@@ -0,0 +1,332 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/build"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+)
+
+const (
+	DefaultDockerfile = "Dockerfile"
+	dockerignoreFile  = ".dockerignore"
+
+	// buildLogTailSize is the number of build output lines included in a build error.
+	buildLogTailSize = 10
+)
+
+// BuildOptions holds the configuration for building an image from a local context
+type BuildOptions struct {
+	ContextDir  string
+	Dockerfile  string
+	Tags        []string
+	Target      string
+	BuildArgs   map[string]*string
+	Labels      map[string]string
+	CacheFrom   []string
+	NoCache     bool
+	ForceRemove bool
+	Platform    string
+	AuthConfigs map[string]registry.AuthConfig
+}
+
+// BuildImage builds an image through the Engine build API and returns the resulting image ID
+func (c *Client) BuildImage(ctx context.Context, opts BuildOptions) (string, error) {
+	dockerfile, err := resolveDockerfile(opts.ContextDir, opts.Dockerfile)
+	if err != nil {
+		return "", err
+	}
+
+	buildContext, err := CreateBuildContext(opts.ContextDir, dockerfile)
+	if err != nil {
+		return "", err
+	}
+	defer buildContext.Close()
+
+	buildResp, err := c.ImageBuild(ctx, buildContext, build.ImageBuildOptions{
+		Tags:        opts.Tags,
+		Dockerfile:  dockerfile,
+		Target:      opts.Target,
+		BuildArgs:   opts.BuildArgs,
+		Labels:      opts.Labels,
+		CacheFrom:   opts.CacheFrom,
+		NoCache:     opts.NoCache,
+		Remove:      true,
+		ForceRemove: opts.ForceRemove,
+		Platform:    opts.Platform,
+		AuthConfigs: opts.AuthConfigs,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start build: %w", err)
+	}
+	defer buildResp.Body.Close()
+
+	return readBuildOutput(ctx, buildResp.Body)
+}
+
+// readBuildOutput drains the build message stream, returning the built image ID or the first build error
+func readBuildOutput(ctx context.Context, body io.Reader) (string, error) {
+	var imageID string
+	var tail []string
+
+	decoder := json.NewDecoder(body)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", fmt.Errorf("failed to decode build output: %w", err)
+		}
+
+		if msg.Error != nil {
+			return "", buildError(msg.Error.Message, tail)
+		}
+		if msg.ErrorMessage != "" {
+			return "", buildError(msg.ErrorMessage, tail)
+		}
+
+		if line := strings.TrimRight(msg.Stream, "\n"); line != "" {
+			tflog.Debug(ctx, "Docker build output", map[string]interface{}{
+				"line": line,
+			})
+			tail = append(tail, line)
+			if len(tail) > buildLogTailSize {
+				tail = tail[1:]
+			}
+		}
+
+		if msg.Aux != nil {
+			var result build.Result
+			if err := json.Unmarshal(*msg.Aux, &result); err == nil && result.ID != "" {
+				imageID = result.ID
+			}
+		}
+	}
+
+	return imageID, nil
+}
+
+func buildError(message string, tail []string) error {
+	if len(tail) == 0 {
+		return errors.New(message)
+	}
+	return fmt.Errorf("%s\n\nLast build output:\n%s", message, strings.Join(tail, "\n"))
+}
+
+// resolveDockerfile returns the Dockerfile path relative to the build context
+func resolveDockerfile(contextDir, dockerfile string) (string, error) {
+	if dockerfile == "" {
+		return DefaultDockerfile, nil
+	}
+
+	rel := filepath.Clean(dockerfile)
+	if filepath.IsAbs(dockerfile) {
+		absContext, err := filepath.Abs(contextDir)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve build context %s: %w", contextDir, err)
+		}
+		if rel, err = filepath.Rel(absContext, dockerfile); err != nil {
+			return "", fmt.Errorf("dockerfile %s must be inside the build context %s", dockerfile, contextDir)
+		}
+	}
+
+	// The daemon only sees the context, and a Dockerfile outside it would not be hashed for change detection
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("dockerfile %s must be inside the build context %s", dockerfile, contextDir)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// CreateBuildContext returns a tar stream of the build context directory, honoring .dockerignore.
+// The Dockerfile and .dockerignore are always included so the daemon can read them.
+func CreateBuildContext(contextDir, dockerfile string) (io.ReadCloser, error) {
+	info, err := os.Stat(contextDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build context %s: %w", contextDir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("build context %s is not a directory", contextDir)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pw)
+		err := walkBuildContext(contextDir, dockerfile, func(relPath, fullPath string, info fs.FileInfo) error {
+			return addToTar(tw, relPath, fullPath, info)
+		})
+		if err == nil {
+			err = tw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
+// walkBuildContext calls fn for every file and directory in the build context that is not excluded by .dockerignore
+func walkBuildContext(contextDir, dockerfile string, fn func(relPath, fullPath string, info fs.FileInfo) error) error {
+	excludes, err := readDockerignore(contextDir)
+	if err != nil {
+		return err
+	}
+
+	var matcher *patternmatcher.PatternMatcher
+	if len(excludes) > 0 {
+		matcher, err = patternmatcher.New(excludes)
+		if err != nil {
+			return fmt.Errorf("invalid .dockerignore pattern: %w", err)
+		}
+	}
+
+	dockerfile = filepath.ToSlash(filepath.Clean(dockerfile))
+	alwaysInclude := map[string]bool{
+		dockerfile:       true,
+		dockerignoreFile: true,
+	}
+
+	return filepath.WalkDir(contextDir, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(contextDir, fullPath)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if matcher != nil && !alwaysInclude[relPath] {
+			excluded, err := matcher.MatchesOrParentMatches(relPath)
+			if err != nil {
+				return err
+			}
+			if excluded {
+				// A later "!pattern" may re-include something below an excluded directory
+				if d.IsDir() && !matcher.Exclusions() && !strings.HasPrefix(dockerfile, relPath+"/") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(relPath, fullPath, info)
+	})
+}
+
+func readDockerignore(contextDir string) ([]string, error) {
+	f, err := os.Open(filepath.Join(contextDir, dockerignoreFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open .dockerignore: %w", err)
+	}
+	defer f.Close()
+
+	excludes, err := ignorefile.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read .dockerignore: %w", err)
+	}
+	return excludes, nil
+}
+
+func addToTar(tw *tar.Writer, relPath, fullPath string, info fs.FileInfo) error {
+	var link string
+	if info.Mode()&fs.ModeSymlink != 0 {
+		var err error
+		link, err = os.Readlink(fullPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = relPath
+	if info.IsDir() {
+		header.Name += "/"
+	}
+	// Match the docker CLI, which sends the context owned by root
+	header.Uid, header.Gid = 0, 0
+	header.Uname, header.Gname = "", ""
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// HashBuildContext returns a digest over the paths, modes and contents of the build context, honoring .dockerignore
+func HashBuildContext(contextDir, dockerfile string) (string, error) {
+	dockerfile, err := resolveDockerfile(contextDir, dockerfile)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	err = walkBuildContext(contextDir, dockerfile, func(relPath, fullPath string, info fs.FileInfo) error {
+		fmt.Fprintf(h, "%s\x00%o\x00", relPath, info.Mode())
+
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(fullPath)
+			if err != nil {
+				return err
+			}
+			io.WriteString(h, link)
+		case info.Mode().IsRegular():
+			f, err := os.Open(fullPath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if _, err := io.Copy(h, f); err != nil {
+				return err
+			}
+		}
+
+		h.Write([]byte{0})
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash build context %s: %w", contextDir, err)
+	}
+
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
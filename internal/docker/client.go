@@ -8,32 +8,51 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	dockerclient "github.com/docker/docker/client"
 	"github.com/docker/go-connections/tlsconfig"
 )
 
 type ClientConfig struct {
-	Host      string
-	TLSVerify bool
-	CertPath  string
-	CACert    string
-	Cert      string
-	Key       string
+	Host          string
+	TLSVerify     bool
+	TLSSkipVerify bool
+	CertPath      string
+	CACert        string
+	Cert          string
+	Key           string
+	SSH           SSHConfig
 }
 
 type Client struct {
 	*dockerclient.Client
-	config ClientConfig
+	config    ClientConfig
+	sshDialer *sshDialer
 }
 
 func NewClient(config ClientConfig) (*Client, error) {
 	opts := []dockerclient.Opt{
-		dockerclient.WithHost(config.Host),
 		dockerclient.WithAPIVersionNegotiation(),
 	}
 
-	if config.TLSVerify {
+	// ssh:// hosts are reached through `docker system dial-stdio` on the remote host
+	var dialer *sshDialer
+	if strings.HasPrefix(config.Host, "ssh://") {
+		var err error
+		dialer, err = newSSHDialer(config.Host, config.SSH)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure SSH connection: %w", err)
+		}
+		opts = append(opts,
+			dockerclient.WithHost(sshDialHost),
+			dockerclient.WithDialContext(dialer.DialContext),
+		)
+	} else {
+		opts = append(opts, dockerclient.WithHost(config.Host))
+	}
+
+	if config.TLSVerify && dialer == nil {
 		httpClient, err := createTLSHTTPClient(config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create TLS HTTP client: %w", err)
@@ -49,12 +68,16 @@ func NewClient(config ClientConfig) (*Client, error) {
 	// Verify connection
 	_, err = client.Ping(context.Background())
 	if err != nil {
+		if dialer != nil {
+			dialer.Close()
+		}
 		return nil, fmt.Errorf("failed to connect to Docker daemon at %s: %w", config.Host, err)
 	}
 
 	return &Client{
-		Client: client,
-		config: config,
+		Client:    client,
+		config:    config,
+		sshDialer: dialer,
 	}, nil
 }
 
@@ -69,18 +92,24 @@ func createTLSHTTPClient(config ClientConfig) (*http.Client, error) {
 			return nil, err
 		}
 	} else if config.CertPath != "" {
-		// Load certificates from path
+		// Load certificates from path. Context TLS directories may hold only a CA, or only a client certificate.
 		options := tlsconfig.Options{
-			CAFile:             filepath.Join(config.CertPath, "ca.pem"),
-			CertFile:           filepath.Join(config.CertPath, "cert.pem"),
-			KeyFile:            filepath.Join(config.CertPath, "key.pem"),
-			InsecureSkipVerify: !config.TLSVerify,
+			InsecureSkipVerify: config.TLSSkipVerify,
+		}
+		if caFile := filepath.Join(config.CertPath, "ca.pem"); fileExists(caFile) {
+			options.CAFile = caFile
+		}
+		if certFile := filepath.Join(config.CertPath, "cert.pem"); fileExists(certFile) {
+			options.CertFile = certFile
+			options.KeyFile = filepath.Join(config.CertPath, "key.pem")
 		}
 		var err error
 		tlsConfig, err = tlsconfig.Client(options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create TLS config from cert path: %w", err)
 		}
+	} else if config.TLSSkipVerify {
+		tlsConfig = &tls.Config{InsecureSkipVerify: true}
 	} else {
 		return nil, fmt.Errorf("TLS verification enabled but no certificates provided")
 	}
@@ -112,7 +141,13 @@ func createTLSConfigFromContent(caCert, cert, key string) (*tls.Config, error) {
 }
 
 func (c *Client) Close() error {
-	return c.Client.Close()
+	err := c.Client.Close()
+	if c.sshDialer != nil {
+		if sshErr := c.sshDialer.Close(); err == nil {
+			err = sshErr
+		}
+	}
+	return err
 }
 
 func LoadCertFromFile(path string) (string, error) {
@@ -122,3 +157,8 @@ func LoadCertFromFile(path string) (string, error) {
 	}
 	return string(content), nil
 }
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,108 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultContextName is the context that uses DOCKER_HOST or the local socket rather than the context store
+const DefaultContextName = "default"
+
+// DockerContext is the Docker endpoint of a docker CLI context
+type DockerContext struct {
+	Name          string
+	Host          string
+	SkipTLSVerify bool
+	// TLSPath is the directory holding the context's ca.pem, cert.pem and key.pem, or empty without TLS material
+	TLSPath string
+}
+
+type contextMeta struct {
+	Name      string                     `json:"Name"`
+	Endpoints map[string]contextEndpoint `json:"Endpoints"`
+}
+
+type contextEndpoint struct {
+	Host          string `json:"Host"`
+	SkipTLSVerify bool   `json:"SkipTLSVerify"`
+}
+
+// ConfigDir returns the docker CLI configuration directory, honoring DOCKER_CONFIG
+func ConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".docker")
+}
+
+// CurrentContextName returns the context selected by DOCKER_CONTEXT or by currentContext in config.json
+func CurrentContextName() (string, error) {
+	if name := os.Getenv("DOCKER_CONTEXT"); name != "" {
+		return name, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(ConfigDir(), "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultContextName, nil
+		}
+		return "", fmt.Errorf("failed to read docker config: %w", err)
+	}
+
+	var config struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if err := json.Unmarshal(content, &config); err != nil {
+		return "", fmt.Errorf("failed to parse docker config: %w", err)
+	}
+	if config.CurrentContext == "" {
+		return DefaultContextName, nil
+	}
+	return config.CurrentContext, nil
+}
+
+// LoadContext reads the docker endpoint of the named context from the docker CLI context store.
+// Contexts are stored under directories named by the SHA-256 of the context name.
+func LoadContext(name string) (*DockerContext, error) {
+	if name == DefaultContextName {
+		return &DockerContext{Name: name}, nil
+	}
+
+	id := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))
+	contextsDir := filepath.Join(ConfigDir(), "contexts")
+
+	content, err := os.ReadFile(filepath.Join(contextsDir, "meta", id, "meta.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("docker context %q not found", name)
+		}
+		return nil, fmt.Errorf("failed to read docker context %q: %w", name, err)
+	}
+
+	var meta contextMeta
+	if err := json.Unmarshal(content, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse docker context %q: %w", name, err)
+	}
+
+	endpoint, ok := meta.Endpoints["docker"]
+	if !ok {
+		return nil, fmt.Errorf("docker context %q has no docker endpoint", name)
+	}
+
+	dockerContext := &DockerContext{
+		Name:          name,
+		Host:          endpoint.Host,
+		SkipTLSVerify: endpoint.SkipTLSVerify,
+	}
+
+	tlsPath := filepath.Join(contextsDir, "tls", id, "docker")
+	if entries, err := os.ReadDir(tlsPath); err == nil && len(entries) > 0 {
+		dockerContext.TLSPath = tlsPath
+	}
+
+	return dockerContext, nil
+}
@@ -0,0 +1,41 @@
+package docker
+
+import (
+	"io"
+)
+
+// logStreamStderr is the stream type of stderr frames in a multiplexed log stream
+const logStreamStderr = 2
+
+// DemuxLogs copies the log stream of a container without a TTY to stdout and stderr. The daemon
+// multiplexes both streams with an 8-byte header per frame: [STREAM_TYPE, 0, 0, 0, SIZE1, SIZE2, SIZE3, SIZE4].
+// Pass the same writer twice to read the streams interleaved.
+func DemuxLogs(src io.Reader, stdout, stderr io.Writer) error {
+	header := make([]byte, 8)
+	for {
+		n, err := io.ReadFull(src, header)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			// Not a full header, so the stream is not multiplexed; copy the rest as it is
+			if _, err := stdout.Write(header[:n]); err != nil {
+				return err
+			}
+			_, err = io.Copy(stdout, src)
+			return err
+		}
+
+		size := int64(header[4])<<24 | int64(header[5])<<16 | int64(header[6])<<8 | int64(header[7])
+		dst := stdout
+		if header[0] == logStreamStderr {
+			dst = stderr
+		}
+		if _, err := io.CopyN(dst, src, size); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package docker
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultEndpoint is the pool name of the provider's own host configuration
+const DefaultEndpoint = ""
+
+// ClientPool creates Engine clients for named endpoints on first use and shares them between resources.
+// The plugin framework has no shutdown hook, so clients, and the SSH connections behind ssh:// hosts, live
+// for the lifetime of the plugin process and are closed when it exits.
+type ClientPool struct {
+	mu      sync.Mutex
+	entries map[string]*poolEntry
+}
+
+type poolEntry struct {
+	once   sync.Once
+	config ClientConfig
+	client *Client
+	err    error
+}
+
+func NewClientPool() *ClientPool {
+	return &ClientPool{
+		entries: make(map[string]*poolEntry),
+	}
+}
+
+// Register adds an endpoint whose client is created on the first Get
+func (p *ClientPool) Register(name string, config ClientConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.entries[name] = &poolEntry{config: config}
+}
+
+// Has reports whether an endpoint of that name is registered
+func (p *ClientPool) Has(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.entries[name]
+	return ok
+}
+
+// Get returns the client for the named endpoint, connecting to it on first use.
+// A failed connection is remembered so that every resource on the endpoint reports the same error.
+func (p *ClientPool) Get(name string) (*Client, error) {
+	p.mu.Lock()
+	entry, ok := p.entries[name]
+	p.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("docker host %q is not defined in the provider hosts", name)
+	}
+
+	entry.once.Do(func() {
+		entry.client, entry.err = NewClient(entry.config)
+	})
+	return entry.client, entry.err
+}
@@ -0,0 +1,119 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// progressLogInterval is how often progress of the same layer is logged while its status stays unchanged
+const progressLogInterval = 5 * time.Second
+
+// ProgressResult is what the daemon reported at the end of an image pull or push
+type ProgressResult struct {
+	// Digest is the manifest digest, from the aux message of a push or the Digest status of a pull
+	Digest string
+	// Tag is the tag a push reported
+	Tag string
+}
+
+// ReadProgress drains the message stream of an image pull or push. Per-layer progress is logged at a
+// throttled rate, and the first error the daemon reports is returned, naming the layer it occurred on.
+func ReadProgress(ctx context.Context, body io.Reader) (ProgressResult, error) {
+	var result ProgressResult
+
+	lastStatus := make(map[string]string)
+	lastLogged := make(map[string]time.Time)
+
+	decoder := json.NewDecoder(body)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return result, fmt.Errorf("failed to decode progress output: %w", err)
+		}
+
+		if msg.Error != nil || msg.ErrorMessage != "" {
+			return result, progressError(msg, lastStatus)
+		}
+
+		if msg.Aux != nil {
+			var pushResult types.PushResult
+			if err := json.Unmarshal(*msg.Aux, &pushResult); err == nil && pushResult.Digest != "" {
+				result.Digest = pushResult.Digest
+				result.Tag = pushResult.Tag
+			}
+			continue
+		}
+
+		if contentDigest, ok := strings.CutPrefix(msg.Status, "Digest: "); ok {
+			result.Digest = strings.TrimSpace(contentDigest)
+		}
+
+		if msg.ID == "" {
+			if msg.Status != "" {
+				tflog.Debug(ctx, "Docker image progress", map[string]interface{}{
+					"status": msg.Status,
+				})
+			}
+			continue
+		}
+
+		// Log a layer when its status changes, and otherwise only every progressLogInterval
+		now := time.Now()
+		if msg.Status == lastStatus[msg.ID] && now.Sub(lastLogged[msg.ID]) < progressLogInterval {
+			continue
+		}
+		lastStatus[msg.ID] = msg.Status
+		lastLogged[msg.ID] = now
+
+		fields := map[string]interface{}{
+			"layer":  msg.ID,
+			"status": msg.Status,
+		}
+		if msg.Progress != nil && msg.Progress.Total > 0 {
+			fields["current"] = msg.Progress.Current
+			fields["total"] = msg.Progress.Total
+		}
+		tflog.Debug(ctx, "Docker image layer progress", fields)
+	}
+
+	return result, nil
+}
+
+// progressError builds the error of an error message, naming the layer and what was happening to it
+func progressError(msg jsonmessage.JSONMessage, lastStatus map[string]string) error {
+	message := msg.ErrorMessage
+	if msg.Error != nil && msg.Error.Message != "" {
+		message = msg.Error.Message
+	}
+
+	if msg.ID == "" {
+		return errors.New(message)
+	}
+	if status := lastStatus[msg.ID]; status != "" {
+		return fmt.Errorf("layer %s (%s): %s", msg.ID, status, message)
+	}
+	return fmt.Errorf("layer %s: %s", msg.ID, message)
+}
+
+// RepoDigest formats an image name and manifest digest the way image inspect reports RepoDigests,
+// such as nginx@sha256:...
+func RepoDigest(imageName, contentDigest string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return "", fmt.Errorf("invalid image reference %s: %w", imageName, err)
+	}
+	return reference.FamiliarName(named) + "@" + contentDigest, nil
+}
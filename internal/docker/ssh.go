@@ -0,0 +1,553 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	defaultSSHPort    = "22"
+	defaultSSHTimeout = 30 * time.Second
+
+	// sshDialHost is the placeholder host the Docker client uses for requests tunnelled over SSH
+	sshDialHost = "http://docker.example.com"
+)
+
+// SSHConfig holds the options for connecting to a Docker daemon over ssh://
+type SSHConfig struct {
+	// Opts are ssh command line options such as "-o Port=2222", "-p 2222" or "-i ~/.ssh/deploy"
+	Opts []string
+	// PrivateKey is PEM-encoded private key content used before identity files and the agent
+	PrivateKey string
+	// KnownHostsFile overrides the default ~/.ssh/known_hosts
+	KnownHostsFile string
+}
+
+// sshOptions are the ssh options resolved from the host URL and SSHConfig.Opts
+type sshOptions struct {
+	user                  string
+	host                  string
+	port                  string
+	socketPath            string
+	identityFiles         []string
+	knownHostsFiles       []string
+	strictHostKeyChecking bool
+	connectTimeout        time.Duration
+}
+
+// sshDialer runs `docker system dial-stdio` on the remote host for every connection,
+// sharing a single SSH connection that is re-established if it drops
+type sshDialer struct {
+	opts         sshOptions
+	clientConfig *ssh.ClientConfig
+	agentSocket  string
+
+	mu        sync.Mutex
+	client    *ssh.Client
+	agentConn net.Conn
+}
+
+func newSSHDialer(host string, config SSHConfig) (*sshDialer, error) {
+	opts, err := parseSSHOptions(host, config)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &sshDialer{
+		opts:        opts,
+		agentSocket: os.Getenv("SSH_AUTH_SOCK"),
+	}
+
+	var agentSigners func() ([]ssh.Signer, error)
+	if d.agentSocket != "" {
+		agentSigners = d.agentSigners
+	}
+	auth, err := sshAuthMethods(opts, config.PrivateKey, agentSigners)
+	if err != nil {
+		return nil, err
+	}
+
+	clientConfig := &ssh.ClientConfig{
+		User:            opts.user,
+		Auth:            auth,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         opts.connectTimeout,
+	}
+
+	if opts.strictHostKeyChecking {
+		callback, err := knownhosts.New(opts.knownHostsFiles...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read known_hosts: %w", err)
+		}
+		clientConfig.HostKeyCallback = callback
+		// Prefer the key types recorded for the host so verification doesn't fail on a different key type
+		clientConfig.HostKeyAlgorithms = knownHostKeyAlgorithms(callback, net.JoinHostPort(opts.host, opts.port))
+	}
+
+	d.clientConfig = clientConfig
+	return d, nil
+}
+
+// DialContext opens a new stdio tunnel to the remote Docker daemon
+func (d *sshDialer) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	client, err := d.sshClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		// The connection may have dropped, so retry once on a fresh one
+		d.reset(client)
+		if client, err = d.sshClient(ctx); err != nil {
+			return nil, err
+		}
+		if session, err = client.NewSession(); err != nil {
+			return nil, fmt.Errorf("failed to open SSH session: %w", err)
+		}
+	}
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	stderr := &limitedBuffer{limit: 4096}
+	session.Stderr = stderr
+
+	command := "docker system dial-stdio"
+	if d.opts.socketPath != "" {
+		command = fmt.Sprintf("docker --host unix://%s system dial-stdio", d.opts.socketPath)
+	}
+	if err := session.Start(command); err != nil {
+		session.Close()
+		return nil, fmt.Errorf("failed to run %q on %s: %w", command, d.opts.host, err)
+	}
+
+	return &sshConn{
+		session:    session,
+		stdin:      stdin,
+		stdout:     stdout,
+		stderr:     stderr,
+		localAddr:  client.LocalAddr(),
+		remoteAddr: client.RemoteAddr(),
+	}, nil
+}
+
+func (d *sshDialer) sshClient(ctx context.Context) (*ssh.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client != nil {
+		return d.client, nil
+	}
+
+	addr := net.JoinHostPort(d.opts.host, d.opts.port)
+	dialer := net.Dialer{Timeout: d.opts.connectTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, d.clientConfig)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SSH handshake with %s failed: %w", addr, err)
+	}
+
+	d.client = ssh.NewClient(c, chans, reqs)
+	return d.client, nil
+}
+
+func (d *sshDialer) reset(client *ssh.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client == client {
+		d.client.Close()
+		d.client = nil
+	}
+}
+
+// agentSigners returns the keys held by the SSH agent, connecting to it on first use. It is only called
+// during the handshake in sshClient, with d.mu held.
+func (d *sshDialer) agentSigners() ([]ssh.Signer, error) {
+	if d.agentConn == nil {
+		conn, err := net.Dial("unix", d.agentSocket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to SSH agent: %w", err)
+		}
+		d.agentConn = conn
+	}
+	return agent.NewClient(d.agentConn).Signers()
+}
+
+// Close closes the shared SSH connection and the connection to the SSH agent
+func (d *sshDialer) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.agentConn != nil {
+		d.agentConn.Close()
+		d.agentConn = nil
+	}
+
+	if d.client == nil {
+		return nil
+	}
+	err := d.client.Close()
+	d.client = nil
+	return err
+}
+
+// parseSSHOptions resolves the user, host and port from an ssh://[user@]host[:port][/socket] URL and applies opts
+func parseSSHOptions(host string, config SSHConfig) (sshOptions, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return sshOptions{}, fmt.Errorf("invalid SSH host %s: %w", host, err)
+	}
+	if u.Hostname() == "" {
+		return sshOptions{}, fmt.Errorf("invalid SSH host %s: missing hostname", host)
+	}
+
+	opts := sshOptions{
+		host:                  u.Hostname(),
+		port:                  u.Port(),
+		strictHostKeyChecking: true,
+		connectTimeout:        defaultSSHTimeout,
+	}
+	if u.User != nil {
+		opts.user = u.User.Username()
+	}
+	if u.Path != "" && u.Path != "/" {
+		opts.socketPath = u.Path
+	}
+
+	if err := applySSHOpts(&opts, config.Opts); err != nil {
+		return sshOptions{}, err
+	}
+
+	if opts.port == "" {
+		opts.port = defaultSSHPort
+	}
+	if opts.user == "" {
+		current, err := user.Current()
+		if err != nil {
+			return sshOptions{}, fmt.Errorf("no SSH user given in %s and the current user is unknown: %w", host, err)
+		}
+		opts.user = current.Username
+	}
+
+	home, _ := os.UserHomeDir()
+	if config.KnownHostsFile != "" {
+		opts.knownHostsFiles = append(opts.knownHostsFiles, expandHome(config.KnownHostsFile, home))
+	}
+	if len(opts.knownHostsFiles) == 0 {
+		opts.knownHostsFiles = []string{filepath.Join(home, ".ssh", "known_hosts")}
+	}
+	for i, f := range opts.identityFiles {
+		opts.identityFiles[i] = expandHome(f, home)
+	}
+	for i, f := range opts.knownHostsFiles {
+		opts.knownHostsFiles[i] = expandHome(f, home)
+	}
+
+	return opts, nil
+}
+
+// applySSHOpts applies the supported subset of ssh command line options
+func applySSHOpts(opts *sshOptions, args []string) error {
+	var fields []string
+	for _, arg := range args {
+		fields = append(fields, strings.Fields(arg)...)
+	}
+
+	next := func(i int, flag string) (string, error) {
+		if i+1 >= len(fields) {
+			return "", fmt.Errorf("ssh option %s requires a value", flag)
+		}
+		return fields[i+1], nil
+	}
+
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		switch {
+		case field == "-o" || field == "-p" || field == "-i" || field == "-l":
+			value, err := next(i, field)
+			if err != nil {
+				return err
+			}
+			i++
+			if field == "-o" {
+				if err := applySSHOption(opts, value); err != nil {
+					return err
+				}
+				continue
+			}
+			applySSHFlag(opts, field, value)
+		case strings.HasPrefix(field, "-o"):
+			if err := applySSHOption(opts, strings.TrimPrefix(field, "-o")); err != nil {
+				return err
+			}
+		case len(field) > 2 && (strings.HasPrefix(field, "-p") || strings.HasPrefix(field, "-i") || strings.HasPrefix(field, "-l")):
+			applySSHFlag(opts, field[:2], field[2:])
+		case !strings.HasPrefix(field, "-"):
+			if err := applySSHOption(opts, field); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported ssh option %s", field)
+		}
+	}
+
+	return nil
+}
+
+func applySSHFlag(opts *sshOptions, flag, value string) {
+	switch flag {
+	case "-p":
+		opts.port = value
+	case "-i":
+		opts.identityFiles = append(opts.identityFiles, value)
+	case "-l":
+		opts.user = value
+	}
+}
+
+// applySSHOption applies a single Key=Value option as used with ssh -o
+func applySSHOption(opts *sshOptions, option string) error {
+	key, value, ok := strings.Cut(option, "=")
+	if !ok {
+		return fmt.Errorf("invalid ssh option %s: expected Key=Value", option)
+	}
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+	switch strings.ToLower(key) {
+	case "port":
+		opts.port = value
+	case "user":
+		opts.user = value
+	case "identityfile":
+		opts.identityFiles = append(opts.identityFiles, value)
+	case "userknownhostsfile":
+		opts.knownHostsFiles = append(opts.knownHostsFiles, strings.Fields(value)...)
+	case "stricthostkeychecking":
+		switch strings.ToLower(value) {
+		case "yes", "ask":
+			opts.strictHostKeyChecking = true
+		case "no", "off":
+			opts.strictHostKeyChecking = false
+		default:
+			return fmt.Errorf("unsupported StrictHostKeyChecking value %s", value)
+		}
+	case "connecttimeout":
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid ConnectTimeout %s: %w", value, err)
+		}
+		opts.connectTimeout = time.Duration(seconds) * time.Second
+	default:
+		return fmt.Errorf("unsupported ssh option %s", key)
+	}
+
+	return nil
+}
+
+// sshAuthMethods returns the configured private key, identity files and SSH agent keys, in that order.
+// The default identity files are only tried when no identity file was given. agentSigners is nil when no
+// SSH agent is running.
+func sshAuthMethods(opts sshOptions, privateKey string, agentSigners func() ([]ssh.Signer, error)) ([]ssh.AuthMethod, error) {
+	var signers []ssh.Signer
+
+	if privateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SSH private key: %w", err)
+		}
+		signers = append(signers, signer)
+	}
+
+	for _, file := range opts.identityFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SSH identity file %s: %w", file, err)
+		}
+		signer, err := ssh.ParsePrivateKey(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SSH identity file %s: %w", file, err)
+		}
+		signers = append(signers, signer)
+	}
+
+	methods := []ssh.AuthMethod{}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	if agentSigners != nil {
+		methods = append(methods, ssh.PublicKeysCallback(agentSigners))
+	}
+
+	if privateKey == "" && len(opts.identityFiles) == 0 {
+		home, _ := os.UserHomeDir()
+		var defaults []ssh.Signer
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			content, err := os.ReadFile(filepath.Join(home, ".ssh", name))
+			if err != nil {
+				continue
+			}
+			// Passphrase protected keys are expected to be loaded in the agent
+			if signer, err := ssh.ParsePrivateKey(content); err == nil {
+				defaults = append(defaults, signer)
+			}
+		}
+		if len(defaults) > 0 {
+			methods = append(methods, ssh.PublicKeys(defaults...))
+		}
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("no SSH credentials available: set ssh_private_key, pass an identity file in ssh_opts or start an SSH agent")
+	}
+
+	return methods, nil
+}
+
+// knownHostKeyAlgorithms returns the host key algorithms recorded in known_hosts for addr
+func knownHostKeyAlgorithms(callback ssh.HostKeyCallback, addr string) []string {
+	// Checking a throwaway key makes the callback report every known key for the host
+	placeholder := &net.TCPAddr{IP: net.IPv4zero}
+	err := callback(addr, placeholder, invalidHostKey{})
+
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) {
+		return nil
+	}
+
+	var algorithms []string
+	for _, known := range keyErr.Want {
+		switch known.Key.Type() {
+		case ssh.KeyAlgoRSA:
+			algorithms = append(algorithms, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA)
+		default:
+			algorithms = append(algorithms, known.Key.Type())
+		}
+	}
+	return algorithms
+}
+
+// invalidHostKey is a public key that matches no known_hosts entry
+type invalidHostKey struct{}
+
+func (invalidHostKey) Type() string                        { return "invalid" }
+func (invalidHostKey) Marshal() []byte                     { return []byte("invalid") }
+func (invalidHostKey) Verify([]byte, *ssh.Signature) error { return errors.New("invalid host key") }
+
+func expandHome(path, home string) string {
+	if path == "~" {
+		return home
+	}
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
+
+// sshConn is a net.Conn over the stdio of a remote `docker system dial-stdio` process
+type sshConn struct {
+	session    *ssh.Session
+	stdin      io.WriteCloser
+	stdout     io.Reader
+	stderr     *limitedBuffer
+	localAddr  net.Addr
+	remoteAddr net.Addr
+
+	closeOnce sync.Once
+}
+
+func (c *sshConn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if err == io.EOF && n == 0 {
+		if msg := strings.TrimSpace(c.stderr.String()); msg != "" {
+			return 0, fmt.Errorf("docker system dial-stdio exited: %s", msg)
+		}
+	}
+	return n, err
+}
+
+func (c *sshConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+func (c *sshConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.stdin.Close()
+		err = c.session.Close()
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	})
+	return err
+}
+
+// CloseWrite signals the end of the request body, which the HTTP client uses for hijacked connections
+func (c *sshConn) CloseWrite() error {
+	return c.stdin.Close()
+}
+
+func (c *sshConn) LocalAddr() net.Addr  { return c.localAddr }
+func (c *sshConn) RemoteAddr() net.Addr { return c.remoteAddr }
+
+// Deadlines are not supported on SSH channels; requests are bounded by their contexts instead
+func (c *sshConn) SetDeadline(time.Time) error      { return nil }
+func (c *sshConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *sshConn) SetWriteDeadline(time.Time) error { return nil }
+
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if remaining := b.limit - b.buf.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.buf.Write(p[:remaining])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
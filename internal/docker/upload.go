@@ -0,0 +1,53 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// UploadFile is a file to copy into a container
+type UploadFile struct {
+	// Path is the absolute path of the file inside the container
+	Path    string
+	Content []byte
+	Mode    int64
+}
+
+// UploadArchive returns a tar archive of files for CopyToContainer to extract at /. Parent directories
+// that don't exist in the container are created by the daemon.
+func UploadArchive(files []UploadFile) (io.Reader, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	modTime := time.Now()
+	for _, file := range files {
+		name := strings.TrimPrefix(path.Clean(file.Path), "/")
+		if name == "" || name == "." {
+			return nil, fmt.Errorf("invalid upload path %q", file.Path)
+		}
+
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     file.Mode,
+			Size:     int64(len(file.Content)),
+			ModTime:  modTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, fmt.Errorf("failed to add %s to upload archive: %w", file.Path, err)
+		}
+		if _, err := tw.Write(file.Content); err != nil {
+			return nil, fmt.Errorf("failed to add %s to upload archive: %w", file.Path, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish upload archive: %w", err)
+	}
+	return &buf, nil
+}
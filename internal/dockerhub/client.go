@@ -517,3 +517,9 @@ func (c *Client) ListRepositoryTags(ctx context.Context, namespace, repoName str
 	}
 	return result.Results, result.Count, nil
 }
+
+// DeleteRepositoryTag deletes a tag from a repository
+func (c *Client) DeleteRepositoryTag(ctx context.Context, namespace, repoName, tag string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/tags/%s", namespace, repoName, tag)
+	return c.doRequest(ctx, "DELETE", path, nil, nil)
+}
@@ -16,7 +16,7 @@ import (
 var _ datasource.DataSource = &ComposeDataSource{}
 
 type ComposeDataSource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type ComposeDataSourceModel struct {
@@ -120,7 +120,7 @@ func (d *ComposeDataSource) Configure(ctx context.Context, req datasource.Config
 		return
 	}
 
-	d.client = providerData.DockerClient
+	d.clients = providerData.DockerClients
 }
 
 func (d *ComposeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -131,13 +131,18 @@ func (d *ComposeDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
+	client := engineClient(d.clients, docker.DefaultEndpoint, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	projectName := data.ProjectName.ValueString()
 
 	// Query containers by compose project label using Docker API
 	filterArgs := filters.NewArgs()
 	filterArgs.Add("label", fmt.Sprintf("com.docker.compose.project=%s", projectName))
 
-	containers, err := d.client.ContainerList(ctx, container.ListOptions{
+	containers, err := client.ContainerList(ctx, container.ListOptions{
 		All:     true,
 		Filters: filterArgs,
 	})
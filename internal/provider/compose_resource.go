@@ -3,12 +3,18 @@ package provider
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/loader"
 	composetypes "github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -23,32 +29,49 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"gopkg.in/yaml.v3"
+)
+
+const (
+	// inlineComposeFilename is the name compose_content is loaded under, relative to the project directory
+	inlineComposeFilename = "compose-content.yaml"
+
+	// configHashLabel records the effective service configuration a container was created from, as the compose CLI does
+	configHashLabel = "com.docker.compose.config-hash"
 )
 
 var (
 	_ resource.Resource                = &ComposeResource{}
 	_ resource.ResourceWithImportState = &ComposeResource{}
+	_ resource.ResourceWithModifyPlan  = &ComposeResource{}
 )
 
 type ComposeResource struct {
-	client *docker.Client
+	clients    *docker.ClientPool
+	registries *docker.AuthResolver
 }
 
 type ComposeResourceModel struct {
 	ID              types.String `tfsdk:"id"`
 	ProjectName     types.String `tfsdk:"project_name"`
 	ComposeFile     types.String `tfsdk:"compose_file"`
+	ComposeFiles    types.List   `tfsdk:"compose_files"`
 	ComposeContent  types.String `tfsdk:"compose_content"`
+	EnvFile         types.String `tfsdk:"env_file"`
+	Environment     types.Map    `tfsdk:"environment"`
+	Profiles        types.List   `tfsdk:"profiles"`
+	Scale           types.Map    `tfsdk:"scale"`
 	RemoveOrphans   types.Bool   `tfsdk:"remove_orphans"`
 	RemoveVolumes   types.Bool   `tfsdk:"remove_volumes"`
 	ForceRecreate   types.Bool   `tfsdk:"force_recreate"`
+	WaitTimeout     types.String `tfsdk:"wait_timeout"`
 	ContentHash     types.String `tfsdk:"content_hash"`
 	Services        types.List   `tfsdk:"services"`
 	RunningServices types.Int64  `tfsdk:"running_services"`
+	DockerHost      types.String `tfsdk:"docker_host"`
 }
 
 func NewComposeResource() resource.Resource {
@@ -70,6 +93,13 @@ func (r *ComposeResource) Schema(ctx context.Context, req resource.SchemaRequest
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"docker_host": schema.StringAttribute{
+				Description: "Name of the provider hosts endpoint to manage this resource on. Defaults to the provider's own host. Changing this forces a new resource.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"project_name": schema.StringAttribute{
 				Description: "The name of the Docker Compose project. Used to identify and label resources.",
 				Required:    true,
@@ -78,13 +108,37 @@ func (r *ComposeResource) Schema(ctx context.Context, req resource.SchemaRequest
 				},
 			},
 			"compose_file": schema.StringAttribute{
-				Description: "Path to the Docker Compose file. Either compose_file or compose_content must be specified.",
+				Description: "Path to the Docker Compose file. At least one of compose_file, compose_files or compose_content must be specified.",
 				Optional:    true,
 			},
+			"compose_files": schema.ListAttribute{
+				Description: "Paths to Docker Compose files, merged in order like repeated -f flags. Applied after compose_file.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
 			"compose_content": schema.StringAttribute{
-				Description: "Inline Docker Compose YAML content. Either compose_file or compose_content must be specified.",
+				Description: "Inline Docker Compose YAML content. Merged after any compose files.",
 				Optional:    true,
 			},
+			"env_file": schema.StringAttribute{
+				Description: "Path to an env file used for variable interpolation. Defaults to the .env file in the project directory, if present.",
+				Optional:    true,
+			},
+			"environment": schema.MapAttribute{
+				Description: "Variables used for interpolation. These take precedence over env_file and the provider's environment.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"profiles": schema.ListAttribute{
+				Description: "Compose profiles to enable. Services assigned to other profiles are not deployed.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"scale": schema.MapAttribute{
+				Description: "Number of containers per service, overriding scale and deploy.replicas in the compose file.",
+				Optional:    true,
+				ElementType: types.Int64Type,
+			},
 			"remove_orphans": schema.BoolAttribute{
 				Description: "Remove containers for services not defined in the Compose file. Default is true.",
 				Optional:    true,
@@ -103,8 +157,14 @@ func (r *ComposeResource) Schema(ctx context.Context, req resource.SchemaRequest
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
 			},
+			"wait_timeout": schema.StringAttribute{
+				Description: "How long to wait for each depends_on dependency to become healthy or complete successfully. Default is 5m.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("5m"),
+			},
 			"content_hash": schema.StringAttribute{
-				Description: "Hash of the compose file content for change detection.",
+				Description: "Hash of the resolved compose project and any service build contexts, used for change detection.",
 				Computed:    true,
 			},
 			"services": schema.ListAttribute{
@@ -134,7 +194,8 @@ func (r *ComposeResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	r.client = providerData.DockerClient
+	r.clients = providerData.DockerClients
+	r.registries = providerData.Registries
 }
 
 func (r *ComposeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -145,10 +206,15 @@ func (r *ComposeResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	if data.ComposeFile.IsNull() && data.ComposeContent.IsNull() {
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(composeFiles(data)) == 0 && data.ComposeContent.IsNull() {
 		resp.Diagnostics.AddError(
 			"Missing Compose Configuration",
-			"Either compose_file or compose_content must be specified.",
+			"At least one of compose_file, compose_files or compose_content must be specified.",
 		)
 		return
 	}
@@ -158,20 +224,21 @@ func (r *ComposeResource) Create(ctx context.Context, req resource.CreateRequest
 		"project_name": projectName,
 	})
 
-	// Parse compose file
-	project, err := r.parseComposeFile(data)
+	// Load compose project
+	project, err := r.loadProject(ctx, data)
 	if err != nil {
-		resp.Diagnostics.AddError("Compose Parse Error", fmt.Sprintf("Failed to parse compose file: %s", err))
+		resp.Diagnostics.AddError("Compose Parse Error", fmt.Sprintf("Failed to load compose project: %s", err))
 		return
 	}
 
 	// Calculate content hash
-	contentHash := r.calculateContentHash(data)
-	data.ContentHash = types.StringValue(contentHash)
+	if data.ContentHash.IsUnknown() {
+		data.ContentHash = types.StringValue(r.calculateContentHash(ctx, data))
+	}
 
 	// Create networks
 	for name, netConfig := range project.Networks {
-		if err := r.createNetwork(ctx, projectName, name, netConfig); err != nil {
+		if err := r.createNetwork(ctx, client, projectName, name, netConfig); err != nil {
 			resp.Diagnostics.AddError("Network Create Error", fmt.Sprintf("Failed to create network %s: %s", name, err))
 			return
 		}
@@ -179,26 +246,49 @@ func (r *ComposeResource) Create(ctx context.Context, req resource.CreateRequest
 
 	// Create volumes
 	for name, volConfig := range project.Volumes {
-		if err := r.createVolume(ctx, projectName, name, volConfig); err != nil {
+		if err := r.createVolume(ctx, client, projectName, name, volConfig); err != nil {
 			resp.Diagnostics.AddError("Volume Create Error", fmt.Sprintf("Failed to create volume %s: %s", name, err))
 			return
 		}
 	}
 
+	waitTimeout, err := time.ParseDuration(data.WaitTimeout.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Wait Timeout", fmt.Sprintf("Failed to parse wait_timeout: %s", err))
+		return
+	}
+
+	serviceOrder, err := r.getServiceOrder(project)
+	if err != nil {
+		resp.Diagnostics.AddError("Compose Dependency Error", err.Error())
+		return
+	}
+
+	// Build images for services with a build section
+	if _, err := r.buildServiceImages(ctx, client, projectName, project, serviceOrder); err != nil {
+		resp.Diagnostics.AddError("Service Build Error", err.Error())
+		return
+	}
+
 	// Create and start containers in dependency order
-	serviceOrder := r.getServiceOrder(project)
 	for _, serviceName := range serviceOrder {
 		service := project.Services[serviceName]
-		if err := r.createService(ctx, projectName, serviceName, service, project); err != nil {
-			resp.Diagnostics.AddError("Service Create Error", fmt.Sprintf("Failed to create service %s: %s", serviceName, err))
+		if err := r.waitForDependencies(ctx, client, projectName, serviceName, service, waitTimeout); err != nil {
+			resp.Diagnostics.AddError("Service Dependency Error", err.Error())
 			return
 		}
+		for number := 1; number <= service.GetScale(); number++ {
+			if err := r.createService(ctx, client, projectName, serviceName, number, service, project); err != nil {
+				resp.Diagnostics.AddError("Service Create Error", fmt.Sprintf("Failed to create service %s: %s", serviceName, err))
+				return
+			}
+		}
 	}
 
 	data.ID = types.StringValue(projectName)
 
 	// Refresh stack info
-	r.refreshStackInfo(ctx, &data, project)
+	r.refreshStackInfo(ctx, client, &data, project)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -211,21 +301,28 @@ func (r *ComposeResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	// Parse compose file to get service list
-	project, err := r.parseComposeFile(data)
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Load compose project to get service list
+	project, err := r.loadProject(ctx, data)
 	if err != nil {
 		// File might have been deleted, but stack might still exist
-		tflog.Warn(ctx, "Failed to parse compose file", map[string]interface{}{
+		tflog.Warn(ctx, "Failed to load compose project", map[string]interface{}{
 			"error": err.Error(),
 		})
 	}
 
-	// Update content hash
-	contentHash := r.calculateContentHash(data)
-	data.ContentHash = types.StringValue(contentHash)
+	// The content hash records what was last applied and is only computed here on import.
+	// ModifyPlan compares it against the current files and build contexts.
+	if data.ContentHash.IsNull() {
+		data.ContentHash = types.StringValue(r.calculateContentHash(ctx, data))
+	}
 
 	// Refresh stack info
-	r.refreshStackInfo(ctx, &data, project)
+	r.refreshStackInfo(ctx, client, &data, project)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -238,25 +335,31 @@ func (r *ComposeResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	projectName := data.ProjectName.ValueString()
 	tflog.Debug(ctx, "Updating Docker Compose stack", map[string]interface{}{
 		"project_name": projectName,
 	})
 
-	// Parse new compose file
-	project, err := r.parseComposeFile(data)
+	// Load new compose project
+	project, err := r.loadProject(ctx, data)
 	if err != nil {
-		resp.Diagnostics.AddError("Compose Parse Error", fmt.Sprintf("Failed to parse compose file: %s", err))
+		resp.Diagnostics.AddError("Compose Parse Error", fmt.Sprintf("Failed to load compose project: %s", err))
 		return
 	}
 
 	// Calculate new content hash
-	contentHash := r.calculateContentHash(data)
-	data.ContentHash = types.StringValue(contentHash)
+	if data.ContentHash.IsUnknown() {
+		data.ContentHash = types.StringValue(r.calculateContentHash(ctx, data))
+	}
 
 	// Update networks
 	for name, netConfig := range project.Networks {
-		if err := r.createNetwork(ctx, projectName, name, netConfig); err != nil {
+		if err := r.createNetwork(ctx, client, projectName, name, netConfig); err != nil {
 			tflog.Warn(ctx, "Network might already exist", map[string]interface{}{
 				"network": name,
 				"error":   err.Error(),
@@ -266,7 +369,7 @@ func (r *ComposeResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	// Update volumes
 	for name, volConfig := range project.Volumes {
-		if err := r.createVolume(ctx, projectName, name, volConfig); err != nil {
+		if err := r.createVolume(ctx, client, projectName, name, volConfig); err != nil {
 			tflog.Warn(ctx, "Volume might already exist", map[string]interface{}{
 				"volume": name,
 				"error":  err.Error(),
@@ -274,29 +377,48 @@ func (r *ComposeResource) Update(ctx context.Context, req resource.UpdateRequest
 		}
 	}
 
-	// Recreate containers if force_recreate or config changed
-	if data.ForceRecreate.ValueBool() {
-		// Stop and remove existing containers
-		r.removeProjectContainers(ctx, projectName)
+	waitTimeout, err := time.ParseDuration(data.WaitTimeout.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Wait Timeout", fmt.Sprintf("Failed to parse wait_timeout: %s", err))
+		return
+	}
+
+	serviceOrder, err := r.getServiceOrder(project)
+	if err != nil {
+		resp.Diagnostics.AddError("Compose Dependency Error", err.Error())
+		return
+	}
 
-		// Recreate containers
-		serviceOrder := r.getServiceOrder(project)
-		for _, serviceName := range serviceOrder {
-			service := project.Services[serviceName]
-			if err := r.createService(ctx, projectName, serviceName, service, project); err != nil {
-				resp.Diagnostics.AddError("Service Create Error", fmt.Sprintf("Failed to recreate service %s: %s", serviceName, err))
-				return
-			}
+	// Rebuild images for services with a build section. Unchanged contexts are served from the build cache.
+	builtImages, err := r.buildServiceImages(ctx, client, projectName, project, serviceOrder)
+	if err != nil {
+		resp.Diagnostics.AddError("Service Build Error", err.Error())
+		return
+	}
+
+	// Recreate only the containers whose configuration or built image changed, unless force_recreate is set,
+	// and add or remove containers to match each service's scale
+	for _, serviceName := range serviceOrder {
+		service := project.Services[serviceName]
+
+		if err := r.waitForDependencies(ctx, client, projectName, serviceName, service, waitTimeout); err != nil {
+			resp.Diagnostics.AddError("Service Dependency Error", err.Error())
+			return
+		}
+
+		if err := r.reconcileService(ctx, client, projectName, serviceName, service, project, builtImages[serviceName], data.ForceRecreate.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Service Update Error", fmt.Sprintf("Failed to update service %s: %s", serviceName, err))
+			return
 		}
 	}
 
 	// Remove orphan containers if enabled
 	if data.RemoveOrphans.ValueBool() {
-		r.removeOrphanContainers(ctx, projectName, project)
+		r.removeOrphanContainers(ctx, client, projectName, project)
 	}
 
 	// Refresh stack info
-	r.refreshStackInfo(ctx, &data, project)
+	r.refreshStackInfo(ctx, client, &data, project)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -309,313 +431,184 @@ func (r *ComposeResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	projectName := data.ProjectName.ValueString()
 	tflog.Debug(ctx, "Destroying Docker Compose stack", map[string]interface{}{
 		"project_name": projectName,
 	})
 
 	// Stop and remove all containers for this project
-	r.removeProjectContainers(ctx, projectName)
+	r.removeProjectContainers(ctx, client, projectName)
 
 	// Remove networks
-	r.removeProjectNetworks(ctx, projectName)
+	r.removeProjectNetworks(ctx, client, projectName)
 
 	// Remove volumes if configured
 	if data.RemoveVolumes.ValueBool() {
-		r.removeProjectVolumes(ctx, projectName)
+		r.removeProjectVolumes(ctx, client, projectName)
 	}
 }
 
-func (r *ComposeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("project_name"), req, resp)
-}
-
-// parseComposeFile parses the compose file and returns a Project
-func (r *ComposeResource) parseComposeFile(data ComposeResourceModel) (*composetypes.Project, error) {
-	var content []byte
-	var err error
-
-	if !data.ComposeFile.IsNull() {
-		content, err = os.ReadFile(data.ComposeFile.ValueString())
-		if err != nil {
-			return nil, fmt.Errorf("failed to read compose file: %w", err)
-		}
-	} else if !data.ComposeContent.IsNull() {
-		content = []byte(data.ComposeContent.ValueString())
-	} else {
-		return nil, fmt.Errorf("no compose file or content specified")
-	}
-
-	// Parse YAML into a generic map first
-	var rawConfig map[string]interface{}
-	if err := yaml.Unmarshal(content, &rawConfig); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+// ModifyPlan recomputes the content hash from the compose project and build contexts so that changes to either
+// are planned as an update
+func (r *ComposeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
 	}
 
-	// Convert to compose Project structure
-	project := &composetypes.Project{
-		Name:     data.ProjectName.ValueString(),
-		Services: make(composetypes.Services),
-		Networks: make(composetypes.Networks),
-		Volumes:  make(composetypes.Volumes),
+	var plan ComposeResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Parse services
-	if services, ok := rawConfig["services"].(map[string]interface{}); ok {
-		for name, svcConfig := range services {
-			service, err := parseService(name, svcConfig)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse service %s: %w", name, err)
-			}
-			project.Services[name] = service
-		}
+	if plan.ComposeFile.IsUnknown() || plan.ComposeFiles.IsUnknown() || plan.ComposeContent.IsUnknown() ||
+		plan.EnvFile.IsUnknown() || plan.Environment.IsUnknown() || plan.Profiles.IsUnknown() || plan.Scale.IsUnknown() {
+		return
 	}
 
-	// Parse networks
-	if networks, ok := rawConfig["networks"].(map[string]interface{}); ok {
-		for name, netConfig := range networks {
-			network := parseNetwork(netConfig)
-			project.Networks[name] = network
-		}
+	contentHash := types.StringValue(r.calculateContentHash(ctx, plan))
+	if contentHash.Equal(plan.ContentHash) {
+		return
 	}
 
-	// Parse volumes
-	if volumes, ok := rawConfig["volumes"].(map[string]interface{}); ok {
-		for name, volConfig := range volumes {
-			vol := parseVolume(volConfig)
-			project.Volumes[name] = vol
-		}
-	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_hash"), contentHash)...)
 
-	return project, nil
+	// Stack info is refreshed after the update
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("services"), types.ListUnknown(types.StringType))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("running_services"), types.Int64Unknown())...)
 }
 
-func parseService(name string, config interface{}) (composetypes.ServiceConfig, error) {
-	svc := composetypes.ServiceConfig{
-		Name: name,
-	}
-
-	if config == nil {
-		return svc, nil
-	}
+func (r *ComposeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("project_name"), req, resp)
+}
 
-	cfg, ok := config.(map[string]interface{})
-	if !ok {
-		return svc, nil
+// loadProject loads the compose files and inline content with the compose-go loader, applying interpolation,
+// env files, profiles, extends, include and override merging the same way as the compose CLI
+func (r *ComposeResource) loadProject(ctx context.Context, data ComposeResourceModel) (*composetypes.Project, error) {
+	files := composeFiles(data)
+	if len(files) == 0 && data.ComposeContent.IsNull() {
+		return nil, fmt.Errorf("no compose file or content specified")
 	}
 
-	if image, ok := cfg["image"].(string); ok {
-		svc.Image = image
+	var envFiles []string
+	if !data.EnvFile.IsNull() {
+		envFiles = append(envFiles, data.EnvFile.ValueString())
 	}
 
-	if command, ok := cfg["command"]; ok {
-		switch cmd := command.(type) {
-		case string:
-			svc.Command = composetypes.ShellCommand{cmd}
-		case []interface{}:
-			for _, c := range cmd {
-				if s, ok := c.(string); ok {
-					svc.Command = append(svc.Command, s)
-				}
-			}
+	// Variables from the environment attribute take precedence over env files and the process environment
+	var environment []string
+	for k, v := range data.Environment.Elements() {
+		if s, ok := v.(types.String); ok {
+			environment = append(environment, fmt.Sprintf("%s=%s", k, s.ValueString()))
 		}
 	}
 
-	if entrypoint, ok := cfg["entrypoint"]; ok {
-		switch ep := entrypoint.(type) {
-		case string:
-			svc.Entrypoint = composetypes.ShellCommand{ep}
-		case []interface{}:
-			for _, e := range ep {
-				if s, ok := e.(string); ok {
-					svc.Entrypoint = append(svc.Entrypoint, s)
-				}
-			}
+	var profiles []string
+	for _, v := range data.Profiles.Elements() {
+		if s, ok := v.(types.String); ok {
+			profiles = append(profiles, s.ValueString())
 		}
 	}
 
-	if env, ok := cfg["environment"]; ok {
-		svc.Environment = make(composetypes.MappingWithEquals)
-		switch e := env.(type) {
-		case map[string]interface{}:
-			for k, v := range e {
-				if s, ok := v.(string); ok {
-					svc.Environment[k] = &s
-				}
-			}
-		case []interface{}:
-			for _, item := range e {
-				if s, ok := item.(string); ok {
-					parts := strings.SplitN(s, "=", 2)
-					if len(parts) == 2 {
-						svc.Environment[parts[0]] = &parts[1]
-					}
-				}
-			}
-		}
+	options, err := cli.NewProjectOptions(files,
+		cli.WithOsEnv,
+		cli.WithEnvFiles(envFiles...),
+		cli.WithDotEnv,
+		cli.WithEnv(environment),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve compose environment: %w", err)
 	}
 
-	if ports, ok := cfg["ports"].([]interface{}); ok {
-		for _, p := range ports {
-			switch port := p.(type) {
-			case string:
-				svc.Ports = append(svc.Ports, composetypes.ServicePortConfig{
-					Published: port,
-				})
-			case map[string]interface{}:
-				pc := composetypes.ServicePortConfig{}
-				if target, ok := port["target"].(int); ok {
-					pc.Target = uint32(target)
-				}
-				if published, ok := port["published"].(string); ok {
-					pc.Published = published
-				} else if published, ok := port["published"].(int); ok {
-					pc.Published = fmt.Sprintf("%d", published)
-				}
-				if protocol, ok := port["protocol"].(string); ok {
-					pc.Protocol = protocol
-				}
-				svc.Ports = append(svc.Ports, pc)
-			}
-		}
+	workingDir, err := options.GetWorkingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve compose working directory: %w", err)
 	}
 
-	if volumes, ok := cfg["volumes"].([]interface{}); ok {
-		for _, v := range volumes {
-			switch vol := v.(type) {
-			case string:
-				svc.Volumes = append(svc.Volumes, composetypes.ServiceVolumeConfig{
-					Source: vol,
-				})
-			case map[string]interface{}:
-				vc := composetypes.ServiceVolumeConfig{}
-				if source, ok := vol["source"].(string); ok {
-					vc.Source = source
-				}
-				if target, ok := vol["target"].(string); ok {
-					vc.Target = target
-				}
-				if volType, ok := vol["type"].(string); ok {
-					vc.Type = volType
-				}
-				if ro, ok := vol["read_only"].(bool); ok {
-					vc.ReadOnly = ro
-				}
-				svc.Volumes = append(svc.Volumes, vc)
-			}
-		}
+	// Later files override earlier ones, and inline content overrides all files
+	configDetails := composetypes.ConfigDetails{
+		WorkingDir:  workingDir,
+		Environment: options.Environment,
 	}
-
-	if networks, ok := cfg["networks"]; ok {
-		svc.Networks = make(map[string]*composetypes.ServiceNetworkConfig)
-		switch nets := networks.(type) {
-		case []interface{}:
-			for _, n := range nets {
-				if name, ok := n.(string); ok {
-					svc.Networks[name] = nil
-				}
-			}
-		case map[string]interface{}:
-			for name := range nets {
-				svc.Networks[name] = nil
-			}
+	for _, file := range files {
+		absPath, err := filepath.Abs(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve compose file %s: %w", file, err)
 		}
-	}
-
-	if dependsOn, ok := cfg["depends_on"]; ok {
-		svc.DependsOn = make(composetypes.DependsOnConfig)
-		switch deps := dependsOn.(type) {
-		case []interface{}:
-			for _, d := range deps {
-				if name, ok := d.(string); ok {
-					svc.DependsOn[name] = composetypes.ServiceDependency{}
-				}
-			}
-		case map[string]interface{}:
-			for name := range deps {
-				svc.DependsOn[name] = composetypes.ServiceDependency{}
-			}
+		content, err := os.ReadFile(absPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read compose file: %w", err)
 		}
+		configDetails.ConfigFiles = append(configDetails.ConfigFiles, composetypes.ConfigFile{
+			Filename: absPath,
+			Content:  content,
+		})
 	}
-
-	if restart, ok := cfg["restart"].(string); ok {
-		svc.Restart = restart
-	}
-
-	if hostname, ok := cfg["hostname"].(string); ok {
-		svc.Hostname = hostname
-	}
-
-	if workingDir, ok := cfg["working_dir"].(string); ok {
-		svc.WorkingDir = workingDir
-	}
-
-	if user, ok := cfg["user"].(string); ok {
-		svc.User = user
-	}
-
-	if privileged, ok := cfg["privileged"].(bool); ok {
-		svc.Privileged = privileged
-	}
-
-	return svc, nil
-}
-
-func parseNetwork(config interface{}) composetypes.NetworkConfig {
-	net := composetypes.NetworkConfig{}
-
-	if config == nil {
-		return net
-	}
-
-	cfg, ok := config.(map[string]interface{})
-	if !ok {
-		return net
+	if !data.ComposeContent.IsNull() {
+		configDetails.ConfigFiles = append(configDetails.ConfigFiles, composetypes.ConfigFile{
+			Filename: filepath.Join(workingDir, inlineComposeFilename),
+			Content:  []byte(data.ComposeContent.ValueString()),
+		})
 	}
 
-	if driver, ok := cfg["driver"].(string); ok {
-		net.Driver = driver
+	project, err := loader.LoadWithContext(ctx, configDetails, func(o *loader.Options) {
+		o.SetProjectName(data.ProjectName.ValueString(), true)
+		o.Profiles = profiles
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if external, ok := cfg["external"].(bool); ok {
-		net.External = composetypes.External(external)
+	for name, v := range data.Scale.Elements() {
+		scale, ok := v.(types.Int64)
+		if !ok || scale.IsNull() {
+			continue
+		}
+		service, exists := project.Services[name]
+		if !exists {
+			return nil, fmt.Errorf("scale references service %s, which is not defined or not enabled", name)
+		}
+		if scale.ValueInt64() < 0 {
+			return nil, fmt.Errorf("scale for service %s must not be negative", name)
+		}
+		service.SetScale(int(scale.ValueInt64()))
+		project.Services[name] = service
 	}
 
-	return net
+	return project, nil
 }
 
-func parseVolume(config interface{}) composetypes.VolumeConfig {
-	vol := composetypes.VolumeConfig{}
-
-	if config == nil {
-		return vol
-	}
-
-	cfg, ok := config.(map[string]interface{})
-	if !ok {
-		return vol
-	}
-
-	if driver, ok := cfg["driver"].(string); ok {
-		vol.Driver = driver
+// composeFiles returns the configured compose files in override order
+func composeFiles(data ComposeResourceModel) []string {
+	var files []string
+	if !data.ComposeFile.IsNull() {
+		files = append(files, data.ComposeFile.ValueString())
 	}
-
-	if external, ok := cfg["external"].(bool); ok {
-		vol.External = composetypes.External(external)
+	for _, v := range data.ComposeFiles.Elements() {
+		if s, ok := v.(types.String); ok {
+			files = append(files, s.ValueString())
+		}
 	}
-
-	return vol
+	return files
 }
 
-func (r *ComposeResource) createNetwork(ctx context.Context, projectName, name string, config composetypes.NetworkConfig) error {
+func (r *ComposeResource) createNetwork(ctx context.Context, client *docker.Client, projectName, name string, config composetypes.NetworkConfig) error {
 	if bool(config.External) {
 		return nil // External network, don't create
 	}
 
-	networkName := fmt.Sprintf("%s_%s", projectName, name)
+	networkName := config.Name
+	if networkName == "" {
+		networkName = fmt.Sprintf("%s_%s", projectName, name)
+	}
 
 	// Check if network exists
-	_, err := r.client.NetworkInspect(ctx, networkName, network.InspectOptions{})
+	_, err := client.NetworkInspect(ctx, networkName, network.InspectOptions{})
 	if err == nil {
 		return nil // Network already exists
 	}
@@ -625,7 +618,7 @@ func (r *ComposeResource) createNetwork(ctx context.Context, projectName, name s
 		driver = "bridge"
 	}
 
-	_, err = r.client.NetworkCreate(ctx, networkName, network.CreateOptions{
+	_, err = client.NetworkCreate(ctx, networkName, network.CreateOptions{
 		Driver: driver,
 		Labels: map[string]string{
 			"com.docker.compose.project": projectName,
@@ -636,15 +629,18 @@ func (r *ComposeResource) createNetwork(ctx context.Context, projectName, name s
 	return err
 }
 
-func (r *ComposeResource) createVolume(ctx context.Context, projectName, name string, config composetypes.VolumeConfig) error {
+func (r *ComposeResource) createVolume(ctx context.Context, client *docker.Client, projectName, name string, config composetypes.VolumeConfig) error {
 	if bool(config.External) {
 		return nil // External volume, don't create
 	}
 
-	volumeName := fmt.Sprintf("%s_%s", projectName, name)
+	volumeName := config.Name
+	if volumeName == "" {
+		volumeName = fmt.Sprintf("%s_%s", projectName, name)
+	}
 
 	// Check if volume exists
-	_, err := r.client.VolumeInspect(ctx, volumeName)
+	_, err := client.VolumeInspect(ctx, volumeName)
 	if err == nil {
 		return nil // Volume already exists
 	}
@@ -654,7 +650,7 @@ func (r *ComposeResource) createVolume(ctx context.Context, projectName, name st
 		driver = "local"
 	}
 
-	_, err = r.client.VolumeCreate(ctx, volume.CreateOptions{
+	_, err = client.VolumeCreate(ctx, volume.CreateOptions{
 		Name:   volumeName,
 		Driver: driver,
 		Labels: map[string]string{
@@ -666,22 +662,30 @@ func (r *ComposeResource) createVolume(ctx context.Context, projectName, name st
 	return err
 }
 
-func (r *ComposeResource) createService(ctx context.Context, projectName, serviceName string, service composetypes.ServiceConfig, project *composetypes.Project) error {
-	containerName := fmt.Sprintf("%s-%s-1", projectName, serviceName)
+func (r *ComposeResource) createService(ctx context.Context, client *docker.Client, projectName, serviceName string, number int, service composetypes.ServiceConfig, project *composetypes.Project) error {
+	containerName := fmt.Sprintf("%s-%s-%d", projectName, serviceName, number)
 
 	// Check if container exists
-	_, err := r.client.ContainerInspect(ctx, containerName)
+	_, err := client.ContainerInspect(ctx, containerName)
 	if err == nil {
 		// Container exists, start it if not running
-		return r.client.ContainerStart(ctx, containerName, container.StartOptions{})
+		return client.ContainerStart(ctx, containerName, container.StartOptions{})
+	}
+
+	configHash, err := serviceConfigHash(service)
+	if err != nil {
+		return fmt.Errorf("failed to hash service configuration: %w", err)
 	}
 
 	// Build container config
 	containerConfig := &container.Config{
-		Image: service.Image,
+		Image: getImageNameOrDefault(service, projectName),
 		Labels: map[string]string{
-			"com.docker.compose.project": projectName,
-			"com.docker.compose.service": serviceName,
+			"com.docker.compose.project":          projectName,
+			"com.docker.compose.service":          serviceName,
+			"com.docker.compose.container-number": strconv.Itoa(number),
+			"com.docker.compose.oneoff":           "False",
+			configHashLabel:                       configHash,
 		},
 	}
 
@@ -725,15 +729,7 @@ func (r *ComposeResource) createService(ctx context.Context, projectName, servic
 			portKey := nat.Port(fmt.Sprintf("%d/%s", p.Target, protocol))
 			exposedPorts[portKey] = struct{}{}
 			if p.Published != "" {
-				portBindings[portKey] = []nat.PortBinding{{HostPort: p.Published}}
-			}
-		} else if p.Published != "" {
-			// Parse string format like "8080:80"
-			parts := strings.Split(p.Published, ":")
-			if len(parts) == 2 {
-				portKey := nat.Port(fmt.Sprintf("%s/tcp", parts[1]))
-				exposedPorts[portKey] = struct{}{}
-				portBindings[portKey] = []nat.PortBinding{{HostPort: parts[0]}}
+				portBindings[portKey] = append(portBindings[portKey], nat.PortBinding{HostIP: p.HostIP, HostPort: p.Published})
 			}
 		}
 	}
@@ -748,14 +744,7 @@ func (r *ComposeResource) createService(ctx context.Context, projectName, servic
 
 	// Restart policy
 	if service.Restart != "" {
-		switch service.Restart {
-		case "always":
-			hostConfig.RestartPolicy = container.RestartPolicy{Name: container.RestartPolicyAlways}
-		case "unless-stopped":
-			hostConfig.RestartPolicy = container.RestartPolicy{Name: container.RestartPolicyUnlessStopped}
-		case "on-failure":
-			hostConfig.RestartPolicy = container.RestartPolicy{Name: container.RestartPolicyOnFailure}
-		}
+		hostConfig.RestartPolicy = parseRestartPolicy(service.Restart)
 	}
 
 	// Volume mounts
@@ -764,8 +753,11 @@ func (r *ComposeResource) createService(ctx context.Context, projectName, servic
 		if v.Type == "volume" || v.Type == "" {
 			source := v.Source
 			// Check if it's a named volume from the compose file
-			if _, exists := project.Volumes[v.Source]; exists {
-				source = fmt.Sprintf("%s_%s", projectName, v.Source)
+			if volConfig, exists := project.Volumes[v.Source]; exists {
+				source = volConfig.Name
+				if source == "" {
+					source = fmt.Sprintf("%s_%s", projectName, v.Source)
+				}
 			}
 			m = mount.Mount{
 				Type:     mount.TypeVolume,
@@ -793,7 +785,10 @@ func (r *ComposeResource) createService(ctx context.Context, projectName, servic
 
 	if len(service.Networks) > 0 {
 		for netName := range service.Networks {
-			fullNetName := fmt.Sprintf("%s_%s", projectName, netName)
+			fullNetName := project.Networks[netName].Name
+			if fullNetName == "" {
+				fullNetName = fmt.Sprintf("%s_%s", projectName, netName)
+			}
 			networkConfig.EndpointsConfig[fullNetName] = &network.EndpointSettings{}
 		}
 	} else {
@@ -803,36 +798,236 @@ func (r *ComposeResource) createService(ctx context.Context, projectName, servic
 	}
 
 	// Create container
-	resp, err := r.client.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nil, containerName)
+	resp, err := client.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nil, containerName)
 	if err != nil {
 		return fmt.Errorf("failed to create container: %w", err)
 	}
 
 	// Start container
-	if err := r.client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+	if err := client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
 		return fmt.Errorf("failed to start container: %w", err)
 	}
 
 	return nil
 }
 
-func (r *ComposeResource) getServiceOrder(project *composetypes.Project) []string {
-	// Simple topological sort based on depends_on
+// reconcileService converges the containers of a service to its scale. Containers whose configuration or built
+// image changed are recreated, unchanged ones are started if stopped, and the highest numbered are removed on scale down.
+func (r *ComposeResource) reconcileService(ctx context.Context, client *docker.Client, projectName, serviceName string, service composetypes.ServiceConfig, project *composetypes.Project, imageID string, forceRecreate bool) error {
+	configHash, err := serviceConfigHash(service)
+	if err != nil {
+		return fmt.Errorf("failed to hash service configuration: %w", err)
+	}
+
+	containers, err := r.serviceContainers(ctx, client, projectName, serviceName)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[int]container.Summary)
+	for _, c := range containers {
+		if number, ok := containerNumber(c, projectName, serviceName); ok {
+			existing[number] = c
+		}
+	}
+
+	scale := service.GetScale()
+	for number, c := range existing {
+		if number <= scale {
+			continue
+		}
+		tflog.Debug(ctx, "Scaling down compose service", map[string]interface{}{
+			"service":   serviceName,
+			"container": number,
+		})
+		if err := removeServiceContainer(ctx, client, c.ID); err != nil {
+			return err
+		}
+	}
+
+	for number := 1; number <= scale; number++ {
+		if c, ok := existing[number]; ok {
+			changed := c.Labels[configHashLabel] != configHash || (imageID != "" && c.ImageID != imageID)
+			if !changed && !forceRecreate {
+				if c.State != container.StateRunning {
+					if err := client.ContainerStart(ctx, c.ID, container.StartOptions{}); err != nil {
+						return fmt.Errorf("failed to start container: %w", err)
+					}
+				}
+				continue
+			}
+
+			tflog.Debug(ctx, "Recreating compose service container", map[string]interface{}{
+				"service":   serviceName,
+				"container": number,
+			})
+			if err := removeServiceContainer(ctx, client, c.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := r.createService(ctx, client, projectName, serviceName, number, service, project); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeServiceContainer stops and removes a service container. A container that is already gone counts as removed.
+func removeServiceContainer(ctx context.Context, client *docker.Client, containerID string) error {
+	timeout := 10
+	if err := client.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeout}); err != nil && !strings.Contains(err.Error(), "No such container") {
+		return fmt.Errorf("failed to stop container %s: %w", containerID, err)
+	}
+	if err := client.ContainerRemove(ctx, containerID, container.RemoveOptions{Force: true}); err != nil && !strings.Contains(err.Error(), "No such container") {
+		return fmt.Errorf("failed to remove container %s: %w", containerID, err)
+	}
+	return nil
+}
+
+// serviceContainers lists all containers of a compose service, including stopped ones
+func (r *ComposeResource) serviceContainers(ctx context.Context, client *docker.Client, projectName, serviceName string) ([]container.Summary, error) {
+	containers, err := client.ContainerList(ctx, container.ListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", projectName)),
+			filters.Arg("label", fmt.Sprintf("com.docker.compose.service=%s", serviceName)),
+		),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers for service %s: %w", serviceName, err)
+	}
+	return containers, nil
+}
+
+// containerNumber returns the replica number of a service container, falling back to the container name
+// for containers created without the container-number label
+func containerNumber(c container.Summary, projectName, serviceName string) (int, bool) {
+	if number, err := strconv.Atoi(c.Labels["com.docker.compose.container-number"]); err == nil {
+		return number, true
+	}
+	prefix := fmt.Sprintf("/%s-%s-", projectName, serviceName)
+	for _, name := range c.Names {
+		if number, err := strconv.Atoi(strings.TrimPrefix(name, prefix)); err == nil && strings.HasPrefix(name, prefix) {
+			return number, true
+		}
+	}
+	return 0, false
+}
+
+// serviceConfigHash returns a digest of the service configuration that affects its containers. Like the compose CLI,
+// it leaves out build, pull, scale and dependency settings, which don't require the container to be recreated.
+func serviceConfigHash(service composetypes.ServiceConfig) (string, error) {
+	service.Build = nil
+	service.PullPolicy = ""
+	service.Scale = nil
+	service.DependsOn = nil
+	service.Profiles = nil
+	if service.Deploy != nil {
+		deploy := *service.Deploy
+		deploy.Replicas = nil
+		service.Deploy = &deploy
+	}
+
+	content, err := json.Marshal(service)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(content)), nil
+}
+
+// getImageNameOrDefault returns the image for a service, defaulting to <project>-<service> for built services like the compose CLI
+func getImageNameOrDefault(service composetypes.ServiceConfig, projectName string) string {
+	if service.Image == "" && service.Build != nil {
+		return fmt.Sprintf("%s-%s", projectName, service.Name)
+	}
+	return service.Image
+}
+
+// buildServiceImages builds the images of services with a build section and returns their image IDs by service
+func (r *ComposeResource) buildServiceImages(ctx context.Context, client *docker.Client, projectName string, project *composetypes.Project, serviceOrder []string) (map[string]string, error) {
+	imageIDs := make(map[string]string)
+
+	authConfigs, err := r.registries.AuthConfigs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve registry credentials: %w", err)
+	}
+
+	for _, serviceName := range serviceOrder {
+		service := project.Services[serviceName]
+		build := service.Build
+		if build == nil {
+			continue
+		}
+
+		imageName := getImageNameOrDefault(service, projectName)
+		tflog.Debug(ctx, "Building compose service image", map[string]interface{}{
+			"service": serviceName,
+			"image":   imageName,
+			"context": build.Context,
+		})
+
+		imageID, err := client.BuildImage(ctx, docker.BuildOptions{
+			ContextDir:  build.Context,
+			Dockerfile:  build.Dockerfile,
+			Tags:        append([]string{imageName}, build.Tags...),
+			Target:      build.Target,
+			BuildArgs:   build.Args,
+			Labels:      build.Labels,
+			CacheFrom:   build.CacheFrom,
+			NoCache:     build.NoCache,
+			Platform:    service.Platform,
+			AuthConfigs: authConfigs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build image for service %s: %w", serviceName, err)
+		}
+
+		imageIDs[serviceName] = imageID
+	}
+
+	return imageIDs, nil
+}
+
+// getServiceOrder returns the services in dependency order, or an error naming the services in a dependency cycle
+func (r *ComposeResource) getServiceOrder(project *composetypes.Project) ([]string, error) {
+	// Topological sort based on depends_on. Services still on the path are being visited; reaching one again is a cycle.
 	visited := make(map[string]bool)
+	var path []string
 	order := make([]string, 0, len(project.Services))
 
-	var visit func(name string)
-	visit = func(name string) {
+	var visit func(name string) error
+	visit = func(name string) error {
+		if i := slices.Index(path, name); i >= 0 {
+			return fmt.Errorf("dependency cycle detected: %s -> %s", strings.Join(path[i:], " -> "), name)
+		}
 		if visited[name] {
-			return
+			return nil
 		}
-		visited[name] = true
 
-		service := project.Services[name]
+		service, ok := project.Services[name]
+		if !ok {
+			// Optional dependencies may be disabled by profiles
+			return nil
+		}
+
+		path = append(path, name)
+		deps := make([]string, 0, len(service.DependsOn))
 		for dep := range service.DependsOn {
-			visit(dep)
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
 		}
+		path = path[:len(path)-1]
+
+		visited[name] = true
 		order = append(order, name)
+		return nil
 	}
 
 	// Get all service names and sort for deterministic order
@@ -843,14 +1038,122 @@ func (r *ComposeResource) getServiceOrder(project *composetypes.Project) []strin
 	sort.Strings(serviceNames)
 
 	for _, name := range serviceNames {
-		visit(name)
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// waitForDependencies waits for the service_healthy and service_completed_successfully dependencies of a service
+func (r *ComposeResource) waitForDependencies(ctx context.Context, client *docker.Client, projectName, serviceName string, service composetypes.ServiceConfig, timeout time.Duration) error {
+	deps := make([]string, 0, len(service.DependsOn))
+	for dep := range service.DependsOn {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+
+	for _, dep := range deps {
+		condition := service.DependsOn[dep].Condition
+		if condition != composetypes.ServiceConditionHealthy && condition != composetypes.ServiceConditionCompletedSuccessfully {
+			continue
+		}
+
+		tflog.Debug(ctx, "Waiting for compose service dependency", map[string]interface{}{
+			"service":    serviceName,
+			"dependency": dep,
+			"condition":  condition,
+		})
+		if err := r.waitForServiceCondition(ctx, client, projectName, dep, condition, timeout); err != nil {
+			return fmt.Errorf("service %s depends on %s: %w", serviceName, dep, err)
+		}
+	}
+
+	return nil
+}
+
+// waitForServiceCondition polls the service's containers until all of them satisfy the depends_on condition
+func (r *ComposeResource) waitForServiceCondition(ctx context.Context, client *docker.Client, projectName, serviceName, condition string, timeout time.Duration) error {
+	delay := time.Second
+
+	deadline := time.Now().Add(timeout)
+	for {
+		containers, err := r.serviceContainers(ctx, client, projectName, serviceName)
+		if err != nil {
+			return err
+		}
+		if len(containers) == 0 {
+			return fmt.Errorf("service %s has no containers", serviceName)
+		}
+
+		// pending is the first container that doesn't satisfy the condition yet
+		var pending *container.State
+		for _, c := range containers {
+			containerJSON, err := client.ContainerInspect(ctx, c.ID)
+			if err != nil {
+				return fmt.Errorf("failed to inspect container %s: %w", c.ID, err)
+			}
+			state := containerJSON.State
+
+			switch condition {
+			case composetypes.ServiceConditionHealthy:
+				if state.Health == nil {
+					return fmt.Errorf("service %s has no healthcheck", serviceName)
+				}
+				switch state.Health.Status {
+				case container.Healthy:
+					continue
+				case container.Unhealthy:
+					return fmt.Errorf("service %s is unhealthy%s", serviceName, lastHealthcheckOutput(state.Health))
+				}
+				if !state.Running {
+					return fmt.Errorf("service %s exited with code %d before becoming healthy", serviceName, state.ExitCode)
+				}
+			case composetypes.ServiceConditionCompletedSuccessfully:
+				if !state.Running && !state.Restarting && state.FinishedAt != "" && !strings.HasPrefix(state.FinishedAt, "0001-") {
+					if state.ExitCode != 0 {
+						return fmt.Errorf("service %s didn't complete successfully: exited with code %d", serviceName, state.ExitCode)
+					}
+					continue
+				}
+			}
+
+			if pending == nil {
+				pending = state
+			}
+		}
+
+		if pending == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if condition == composetypes.ServiceConditionHealthy {
+				return fmt.Errorf("service %s did not become healthy within %s%s", serviceName, timeout, lastHealthcheckOutput(pending.Health))
+			}
+			return fmt.Errorf("service %s did not complete within %s", serviceName, timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
 	}
+}
 
-	return order
+// lastHealthcheckOutput formats the output of the most recent healthcheck for a diagnostic
+func lastHealthcheckOutput(health *container.Health) string {
+	if health == nil || len(health.Log) == 0 {
+		return ""
+	}
+	last := health.Log[len(health.Log)-1]
+	return fmt.Sprintf(" (last healthcheck exited with code %d: %s)", last.ExitCode, strings.TrimSpace(last.Output))
 }
 
-func (r *ComposeResource) removeProjectContainers(ctx context.Context, projectName string) {
-	containers, err := r.client.ContainerList(ctx, container.ListOptions{
+func (r *ComposeResource) removeProjectContainers(ctx context.Context, client *docker.Client, projectName string) {
+	containers, err := client.ContainerList(ctx, container.ListOptions{
 		All: true,
 		Filters: filters.NewArgs(
 			filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", projectName)),
@@ -862,13 +1165,13 @@ func (r *ComposeResource) removeProjectContainers(ctx context.Context, projectNa
 
 	timeout := 10
 	for _, c := range containers {
-		_ = r.client.ContainerStop(ctx, c.ID, container.StopOptions{Timeout: &timeout})
-		_ = r.client.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true})
+		_ = client.ContainerStop(ctx, c.ID, container.StopOptions{Timeout: &timeout})
+		_ = client.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true})
 	}
 }
 
-func (r *ComposeResource) removeProjectNetworks(ctx context.Context, projectName string) {
-	networks, err := r.client.NetworkList(ctx, network.ListOptions{
+func (r *ComposeResource) removeProjectNetworks(ctx context.Context, client *docker.Client, projectName string) {
+	networks, err := client.NetworkList(ctx, network.ListOptions{
 		Filters: filters.NewArgs(
 			filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", projectName)),
 		),
@@ -878,12 +1181,12 @@ func (r *ComposeResource) removeProjectNetworks(ctx context.Context, projectName
 	}
 
 	for _, n := range networks {
-		_ = r.client.NetworkRemove(ctx, n.ID)
+		_ = client.NetworkRemove(ctx, n.ID)
 	}
 }
 
-func (r *ComposeResource) removeProjectVolumes(ctx context.Context, projectName string) {
-	volumes, err := r.client.VolumeList(ctx, volume.ListOptions{
+func (r *ComposeResource) removeProjectVolumes(ctx context.Context, client *docker.Client, projectName string) {
+	volumes, err := client.VolumeList(ctx, volume.ListOptions{
 		Filters: filters.NewArgs(
 			filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", projectName)),
 		),
@@ -893,12 +1196,12 @@ func (r *ComposeResource) removeProjectVolumes(ctx context.Context, projectName
 	}
 
 	for _, v := range volumes.Volumes {
-		_ = r.client.VolumeRemove(ctx, v.Name, true)
+		_ = client.VolumeRemove(ctx, v.Name, true)
 	}
 }
 
-func (r *ComposeResource) removeOrphanContainers(ctx context.Context, projectName string, project *composetypes.Project) {
-	containers, err := r.client.ContainerList(ctx, container.ListOptions{
+func (r *ComposeResource) removeOrphanContainers(ctx context.Context, client *docker.Client, projectName string, project *composetypes.Project) {
+	containers, err := client.ContainerList(ctx, container.ListOptions{
 		All: true,
 		Filters: filters.NewArgs(
 			filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", projectName)),
@@ -912,33 +1215,55 @@ func (r *ComposeResource) removeOrphanContainers(ctx context.Context, projectNam
 	for _, c := range containers {
 		serviceName := c.Labels["com.docker.compose.service"]
 		if _, exists := project.Services[serviceName]; !exists {
-			_ = r.client.ContainerStop(ctx, c.ID, container.StopOptions{Timeout: &timeout})
-			_ = r.client.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true})
+			_ = client.ContainerStop(ctx, c.ID, container.StopOptions{Timeout: &timeout})
+			_ = client.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true})
 		}
 	}
 }
 
-func (r *ComposeResource) calculateContentHash(data ComposeResourceModel) string {
-	var content string
+// calculateContentHash hashes the resolved project and build contexts, so that changes to interpolated
+// variables, override files and profiles are detected as well as changes to the files themselves
+func (r *ComposeResource) calculateContentHash(ctx context.Context, data ComposeResourceModel) string {
+	h := sha256.New()
 
-	if !data.ComposeFile.IsNull() {
-		data, err := os.ReadFile(data.ComposeFile.ValueString())
-		if err == nil {
-			content = string(data)
+	project, err := r.loadProject(ctx, data)
+	if err != nil {
+		// Fall back to the raw configuration so that fixing an invalid file still plans an update
+		for _, file := range composeFiles(data) {
+			if content, err := os.ReadFile(file); err == nil {
+				h.Write(content)
+			}
 		}
-	} else if !data.ComposeContent.IsNull() {
-		content = data.ComposeContent.ValueString()
+		h.Write([]byte(data.ComposeContent.ValueString()))
+		return fmt.Sprintf("%x", h.Sum(nil))
+	}
+
+	content, err := project.MarshalYAML()
+	if err == nil {
+		h.Write(content)
 	}
 
-	hash := sha256.Sum256([]byte(content))
-	return fmt.Sprintf("%x", hash)
+	// Include build contexts so that source changes trigger a rebuild
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		if service.Build == nil {
+			continue
+		}
+		contextHash, err := docker.HashBuildContext(service.Build.Context, service.Build.Dockerfile)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(h, "%s\x00%s\x00", name, contextHash)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
-func (r *ComposeResource) refreshStackInfo(ctx context.Context, data *ComposeResourceModel, project *composetypes.Project) {
+func (r *ComposeResource) refreshStackInfo(ctx context.Context, client *docker.Client, data *ComposeResourceModel, project *composetypes.Project) {
 	projectName := data.ProjectName.ValueString()
 
 	// Get running containers count
-	containers, err := r.client.ContainerList(ctx, container.ListOptions{
+	containers, err := client.ContainerList(ctx, container.ListOptions{
 		Filters: filters.NewArgs(
 			filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", projectName)),
 			filters.Arg("status", "running"),
@@ -959,28 +1284,3 @@ func (r *ComposeResource) refreshStackInfo(ctx context.Context, data *ComposeRes
 		data.Services = servicesList
 	}
 }
-
-// Ensure default network exists for the project
-func (r *ComposeResource) ensureDefaultNetwork(ctx context.Context, projectName string) error {
-	networkName := fmt.Sprintf("%s_default", projectName)
-
-	_, err := r.client.NetworkInspect(ctx, networkName, network.InspectOptions{})
-	if err == nil {
-		return nil
-	}
-
-	_, err = r.client.NetworkCreate(ctx, networkName, network.CreateOptions{
-		Driver: "bridge",
-		Labels: map[string]string{
-			"com.docker.compose.project": projectName,
-			"com.docker.compose.network": "default",
-		},
-	})
-
-	return err
-}
-
-func init() {
-	// Ensure time package is used
-	_ = time.Second
-}
@@ -23,14 +23,15 @@ var (
 )
 
 type ConfigResource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type ConfigResourceModel struct {
-	ID     tftypes.String `tfsdk:"id"`
-	Name   tftypes.String `tfsdk:"name"`
-	Data   tftypes.String `tfsdk:"data"`
-	Labels tftypes.Map    `tfsdk:"labels"`
+	ID         tftypes.String `tfsdk:"id"`
+	Name       tftypes.String `tfsdk:"name"`
+	Data       tftypes.String `tfsdk:"data"`
+	Labels     tftypes.Map    `tfsdk:"labels"`
+	DockerHost tftypes.String `tfsdk:"docker_host"`
 }
 
 func NewConfigResource() resource.Resource {
@@ -52,6 +53,13 @@ func (r *ConfigResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"docker_host": schema.StringAttribute{
+				Description: "Name of the provider hosts endpoint to manage this resource on. Defaults to the provider's own host. Changing this forces a new resource. To import onto a named host, prefix the import ID with '<host>/'.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "The name of the Docker config.",
 				Required:    true,
@@ -89,7 +97,7 @@ func (r *ConfigResource) Configure(ctx context.Context, req resource.ConfigureRe
 		return
 	}
 
-	r.client = providerData.DockerClient
+	r.clients = providerData.DockerClients
 }
 
 func (r *ConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -100,6 +108,11 @@ func (r *ConfigResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Decode base64 data
 	configData, err := base64.StdEncoding.DecodeString(data.Data.ValueString())
 	if err != nil {
@@ -135,7 +148,7 @@ func (r *ConfigResource) Create(ctx context.Context, req resource.CreateRequest,
 		"name": data.Name.ValueString(),
 	})
 
-	configResponse, err := r.client.ConfigCreate(ctx, configSpec)
+	configResponse, err := client.ConfigCreate(ctx, configSpec)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Docker Config Creation Failed",
@@ -162,7 +175,12 @@ func (r *ConfigResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	config, _, err := r.client.ConfigInspectWithRaw(ctx, data.ID.ValueString())
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config, _, err := client.ConfigInspectWithRaw(ctx, data.ID.ValueString())
 	if err != nil {
 		if strings.Contains(err.Error(), "No such config") || strings.Contains(err.Error(), "config not found") {
 			tflog.Debug(ctx, "Config not found, removing from state", map[string]interface{}{
@@ -205,8 +223,13 @@ func (r *ConfigResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get current config to get version
-	config, _, err := r.client.ConfigInspectWithRaw(ctx, data.ID.ValueString())
+	config, _, err := client.ConfigInspectWithRaw(ctx, data.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Docker Config Update Failed",
@@ -233,7 +256,7 @@ func (r *ConfigResource) Update(ctx context.Context, req resource.UpdateRequest,
 		Data: config.Spec.Data, // Keep existing data
 	}
 
-	err = r.client.ConfigUpdate(ctx, data.ID.ValueString(), config.Version, configSpec)
+	err = client.ConfigUpdate(ctx, data.ID.ValueString(), config.Version, configSpec)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Docker Config Update Failed",
@@ -253,12 +276,17 @@ func (r *ConfigResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Debug(ctx, "Deleting Docker config", map[string]interface{}{
 		"id":   data.ID.ValueString(),
 		"name": data.Name.ValueString(),
 	})
 
-	err := r.client.ConfigRemove(ctx, data.ID.ValueString())
+	err := client.ConfigRemove(ctx, data.ID.ValueString())
 	if err != nil {
 		if strings.Contains(err.Error(), "No such config") || strings.Contains(err.Error(), "config not found") {
 			tflog.Debug(ctx, "Config already removed", map[string]interface{}{
@@ -279,5 +307,6 @@ func (r *ConfigResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (r *ConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	_, id := importHostID(ctx, r.clients, req.ID, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
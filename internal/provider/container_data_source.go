@@ -14,7 +14,7 @@ import (
 var _ datasource.DataSource = &ContainerDataSource{}
 
 type ContainerDataSource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type ContainerDataSourceModel struct {
@@ -108,7 +108,7 @@ func (d *ContainerDataSource) Configure(ctx context.Context, req datasource.Conf
 		return
 	}
 
-	d.client = providerData.DockerClient
+	d.clients = providerData.DockerClients
 }
 
 func (d *ContainerDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -119,9 +119,14 @@ func (d *ContainerDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
+	client := engineClient(d.clients, docker.DefaultEndpoint, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	containerName := data.Name.ValueString()
 
-	containerJSON, err := d.client.ContainerInspect(ctx, containerName)
+	containerJSON, err := client.ContainerInspect(ctx, containerName)
 	if err != nil {
 		if strings.Contains(err.Error(), "No such container") || strings.Contains(err.Error(), "not found") {
 			resp.Diagnostics.AddError("Container Not Found", fmt.Sprintf("Container %s not found", containerName))
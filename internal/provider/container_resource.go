@@ -1,8 +1,16 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,11 +19,17 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/go-connections/nat"
 	"github.com/elioseverojunior/terraform-provider-docker/internal/docker"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -26,47 +40,74 @@ import (
 var (
 	_ resource.Resource                = &ContainerResource{}
 	_ resource.ResourceWithImportState = &ContainerResource{}
+	_ resource.ResourceWithModifyPlan  = &ContainerResource{}
+)
+
+const (
+	// healthcheckLogEntries is the number of healthcheck results included when a container doesn't become ready
+	healthcheckLogEntries = 3
+
+	// outputTailLines is the number of output lines included when a run_to_completion container fails
+	outputTailLines = 10
+)
+
+// Desired run states of a container
+const (
+	containerStateRunning = "running"
+	containerStateStopped = "stopped"
+	containerStatePaused  = "paused"
 )
 
 type ContainerResource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type ContainerResourceModel struct {
-	ID          types.String      `tfsdk:"id"`
-	Name        types.String      `tfsdk:"name"`
-	Image       types.String      `tfsdk:"image"`
-	Command     types.List        `tfsdk:"command"`
-	Entrypoint  types.List        `tfsdk:"entrypoint"`
-	Env         types.Map         `tfsdk:"env"`
-	Labels      types.Map         `tfsdk:"labels"`
-	Hostname    types.String      `tfsdk:"hostname"`
-	Domainname  types.String      `tfsdk:"domainname"`
-	User        types.String      `tfsdk:"user"`
-	WorkingDir  types.String      `tfsdk:"working_dir"`
-	Restart     types.String      `tfsdk:"restart"`
-	Privileged  types.Bool        `tfsdk:"privileged"`
-	Tty         types.Bool        `tfsdk:"tty"`
-	StdinOpen   types.Bool        `tfsdk:"stdin_open"`
-	NetworkMode types.String      `tfsdk:"network_mode"`
-	DNS         types.List        `tfsdk:"dns"`
-	DNSSearch   types.List        `tfsdk:"dns_search"`
-	ExtraHosts  types.List        `tfsdk:"extra_hosts"`
-	Memory      types.Int64       `tfsdk:"memory"`
-	MemorySwap  types.Int64       `tfsdk:"memory_swap"`
-	CPUShares   types.Int64       `tfsdk:"cpu_shares"`
-	CPUPeriod   types.Int64       `tfsdk:"cpu_period"`
-	CPUQuota    types.Int64       `tfsdk:"cpu_quota"`
-	Remove      types.Bool        `tfsdk:"remove"`
-	MustRun     types.Bool        `tfsdk:"must_run"`
-	Ports       []PortModel       `tfsdk:"ports"`
-	Volumes     []VolumeModel     `tfsdk:"volumes"`
-	Networks    types.Set         `tfsdk:"networks"`
-	Healthcheck *HealthcheckModel `tfsdk:"healthcheck"`
-	ContainerID types.String      `tfsdk:"container_id"`
-	IPAddress   types.String      `tfsdk:"ip_address"`
-	Gateway     types.String      `tfsdk:"gateway"`
-	ExitCode    types.Int64       `tfsdk:"exit_code"`
+	ID                types.String           `tfsdk:"id"`
+	Name              types.String           `tfsdk:"name"`
+	Image             types.String           `tfsdk:"image"`
+	Command           types.List             `tfsdk:"command"`
+	Entrypoint        types.List             `tfsdk:"entrypoint"`
+	Env               types.Map              `tfsdk:"env"`
+	Labels            types.Map              `tfsdk:"labels"`
+	Hostname          types.String           `tfsdk:"hostname"`
+	Domainname        types.String           `tfsdk:"domainname"`
+	User              types.String           `tfsdk:"user"`
+	WorkingDir        types.String           `tfsdk:"working_dir"`
+	Restart           types.String           `tfsdk:"restart"`
+	Privileged        types.Bool             `tfsdk:"privileged"`
+	Tty               types.Bool             `tfsdk:"tty"`
+	StdinOpen         types.Bool             `tfsdk:"stdin_open"`
+	NetworkMode       types.String           `tfsdk:"network_mode"`
+	DNS               types.List             `tfsdk:"dns"`
+	DNSSearch         types.List             `tfsdk:"dns_search"`
+	ExtraHosts        types.List             `tfsdk:"extra_hosts"`
+	Memory            types.Int64            `tfsdk:"memory"`
+	MemorySwap        types.Int64            `tfsdk:"memory_swap"`
+	CPUShares         types.Int64            `tfsdk:"cpu_shares"`
+	CPUPeriod         types.Int64            `tfsdk:"cpu_period"`
+	CPUQuota          types.Int64            `tfsdk:"cpu_quota"`
+	Remove            types.Bool             `tfsdk:"remove"`
+	MustRun           types.Bool             `tfsdk:"must_run"`
+	State             types.String           `tfsdk:"state"`
+	Wait              types.Bool             `tfsdk:"wait"`
+	WaitTimeout       types.String           `tfsdk:"wait_timeout"`
+	RunToCompletion   types.Bool             `tfsdk:"run_to_completion"`
+	CompletionTimeout types.String           `tfsdk:"completion_timeout"`
+	AllowedExitCodes  types.Set              `tfsdk:"allowed_exit_codes"`
+	Stdout            types.String           `tfsdk:"stdout"`
+	Stderr            types.String           `tfsdk:"stderr"`
+	Ports             []PortModel            `tfsdk:"ports"`
+	Volumes           []VolumeModel          `tfsdk:"volumes"`
+	Upload            []UploadModel          `tfsdk:"upload"`
+	Networks          types.Set              `tfsdk:"networks"`
+	NetworksAdvanced  []NetworkAdvancedModel `tfsdk:"networks_advanced"`
+	Healthcheck       *HealthcheckModel      `tfsdk:"healthcheck"`
+	ContainerID       types.String           `tfsdk:"container_id"`
+	IPAddress         types.String           `tfsdk:"ip_address"`
+	Gateway           types.String           `tfsdk:"gateway"`
+	ExitCode          types.Int64            `tfsdk:"exit_code"`
+	DockerHost        types.String           `tfsdk:"docker_host"`
 }
 
 type PortModel struct {
@@ -83,6 +124,25 @@ type VolumeModel struct {
 	ReadOnly      types.Bool   `tfsdk:"read_only"`
 }
 
+type NetworkAdvancedModel struct {
+	Name        types.String `tfsdk:"name"`
+	Aliases     types.Set    `tfsdk:"aliases"`
+	IPv4Address types.String `tfsdk:"ipv4_address"`
+	IPv6Address types.String `tfsdk:"ipv6_address"`
+	Links       types.List   `tfsdk:"links"`
+	DriverOpts  types.Map    `tfsdk:"driver_opts"`
+}
+
+type UploadModel struct {
+	File          types.String `tfsdk:"file"`
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+	Source        types.String `tfsdk:"source"`
+	Permissions   types.String `tfsdk:"permissions"`
+	Executable    types.Bool   `tfsdk:"executable"`
+	ContentHash   types.String `tfsdk:"content_hash"`
+}
+
 type HealthcheckModel struct {
 	Test        types.List   `tfsdk:"test"`
 	Interval    types.String `tfsdk:"interval"`
@@ -110,6 +170,13 @@ func (r *ContainerResource) Schema(ctx context.Context, req resource.SchemaReque
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"docker_host": schema.StringAttribute{
+				Description: "Name of the provider hosts endpoint to manage this resource on. Defaults to the provider's own host. Changing this forces a new resource. To import onto a named host, prefix the import ID with '<host>/'.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "The name of the container.",
 				Required:    true,
@@ -128,37 +195,61 @@ func (r *ContainerResource) Schema(ctx context.Context, req resource.SchemaReque
 				Description: "The command to run in the container.",
 				Optional:    true,
 				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
 			},
 			"entrypoint": schema.ListAttribute{
 				Description: "The entrypoint for the container.",
 				Optional:    true,
 				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
 			},
 			"env": schema.MapAttribute{
 				Description: "Environment variables to set in the container.",
 				Optional:    true,
 				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
 			},
 			"labels": schema.MapAttribute{
 				Description: "User-defined key/value metadata.",
 				Optional:    true,
 				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
 			},
 			"hostname": schema.StringAttribute{
 				Description: "Hostname to set for the container.",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"domainname": schema.StringAttribute{
 				Description: "Domain name for the container.",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"user": schema.StringAttribute{
 				Description: "User that commands are run as inside the container.",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"working_dir": schema.StringAttribute{
 				Description: "Working directory inside the container.",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"restart": schema.StringAttribute{
 				Description: "Restart policy for the container. Values are: no, on-failure[:max-retries], always, unless-stopped.",
@@ -171,67 +262,103 @@ func (r *ContainerResource) Schema(ctx context.Context, req resource.SchemaReque
 				Optional:    true,
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
 			},
 			"tty": schema.BoolAttribute{
 				Description: "Allocate a pseudo-TTY.",
 				Optional:    true,
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
 			},
 			"stdin_open": schema.BoolAttribute{
 				Description: "Keep STDIN open even if not attached.",
 				Optional:    true,
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
 			},
 			"network_mode": schema.StringAttribute{
 				Description: "Network mode of the container (bridge, host, none, container:<name|id>).",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"dns": schema.ListAttribute{
 				Description: "Set of DNS servers.",
 				Optional:    true,
 				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
 			},
 			"dns_search": schema.ListAttribute{
 				Description: "Set of DNS search domains.",
 				Optional:    true,
 				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
 			},
 			"extra_hosts": schema.ListAttribute{
 				Description: "A list of hostnames/IP mappings to add to the container's /etc/hosts file. Format: hostname:IP.",
 				Optional:    true,
 				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
 			},
 			"memory": schema.Int64Attribute{
 				Description: "Memory limit in bytes.",
 				Optional:    true,
 				Computed:    true,
 				Default:     int64default.StaticInt64(0),
+				PlanModifiers: []planmodifier.Int64{
+					requiresReplaceIfLimitRemoved(),
+				},
 			},
 			"memory_swap": schema.Int64Attribute{
 				Description: "Total memory limit (memory + swap) in bytes. Set to -1 for unlimited swap.",
 				Optional:    true,
 				Computed:    true,
 				Default:     int64default.StaticInt64(0),
+				PlanModifiers: []planmodifier.Int64{
+					requiresReplaceIfLimitRemoved(),
+				},
 			},
 			"cpu_shares": schema.Int64Attribute{
 				Description: "CPU shares (relative weight).",
 				Optional:    true,
 				Computed:    true,
 				Default:     int64default.StaticInt64(0),
+				PlanModifiers: []planmodifier.Int64{
+					requiresReplaceIfLimitRemoved(),
+				},
 			},
 			"cpu_period": schema.Int64Attribute{
 				Description: "CPU CFS period in microseconds.",
 				Optional:    true,
 				Computed:    true,
 				Default:     int64default.StaticInt64(0),
+				PlanModifiers: []planmodifier.Int64{
+					requiresReplaceIfLimitRemoved(),
+				},
 			},
 			"cpu_quota": schema.Int64Attribute{
 				Description: "CPU CFS quota in microseconds.",
 				Optional:    true,
 				Computed:    true,
 				Default:     int64default.StaticInt64(0),
+				PlanModifiers: []planmodifier.Int64{
+					requiresReplaceIfLimitRemoved(),
+				},
 			},
 			"remove": schema.BoolAttribute{
 				Description: "If true, removes the container on destruction. Default is true.",
@@ -240,19 +367,73 @@ func (r *ContainerResource) Schema(ctx context.Context, req resource.SchemaReque
 				Default:     booldefault.StaticBool(true),
 			},
 			"must_run": schema.BoolAttribute{
-				Description: "If true, ensures the container is running. Default is true.",
+				Description: "If true, ensures the container is running. Default is true. Superseded by state when that is set.",
 				Optional:    true,
 				Computed:    true,
 				Default:     booldefault.StaticBool(true),
 			},
+			"state": schema.StringAttribute{
+				Description: "Desired run state of the container: running, stopped or paused. Defaults to running, or stopped when must_run is false or run_to_completion is true. Read reports the actual state, so a container that crashed or was stopped outside Terraform shows up as a change.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"wait": schema.BoolAttribute{
+				Description: "If true, wait after starting the container until it is healthy, or running when it has no healthcheck. Default is false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"wait_timeout": schema.StringAttribute{
+				Description: "How long to wait for the container to become healthy or running when wait is true. Default is 60s.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("60s"),
+			},
+			"run_to_completion": schema.BoolAttribute{
+				Description: "If true, the container is run as a one-shot job: creation waits for it to exit, captures its output into stdout and stderr, and fails unless the exit code is in allowed_exit_codes. state defaults to stopped and wait is ignored. Changing this forces a new resource.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"completion_timeout": schema.StringAttribute{
+				Description: "How long to wait for the container to exit when run_to_completion is true. A container still running after this long is stopped. Default is 5m.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("5m"),
+			},
+			"allowed_exit_codes": schema.SetAttribute{
+				Description: "Exit codes that count as success when run_to_completion is true. Default is [0].",
+				Optional:    true,
+				ElementType: types.Int64Type,
+			},
+			"stdout": schema.StringAttribute{
+				Description: "The standard output of the container when run_to_completion is true. Containers with a TTY report all output here.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"stderr": schema.StringAttribute{
+				Description: "The standard error of the container when run_to_completion is true.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"networks": schema.SetAttribute{
-				Description: "Set of networks to attach to the container.",
+				Description: "Set of networks to attach to the container. A network can not also be listed in networks_advanced.",
 				Optional:    true,
 				ElementType: types.StringType,
 			},
 			"container_id": schema.StringAttribute{
 				Description: "The Docker container ID.",
 				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"ip_address": schema.StringAttribute{
 				Description: "The IP address of the container.",
@@ -270,6 +451,9 @@ func (r *ContainerResource) Schema(ctx context.Context, req resource.SchemaReque
 		Blocks: map[string]schema.Block{
 			"ports": schema.ListNestedBlock{
 				Description: "Port mappings for the container.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
 						"internal": schema.Int64Attribute{
@@ -297,6 +481,9 @@ func (r *ContainerResource) Schema(ctx context.Context, req resource.SchemaReque
 			},
 			"volumes": schema.ListNestedBlock{
 				Description: "Volume mounts for the container.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
 						"volume_name": schema.StringAttribute{
@@ -320,8 +507,87 @@ func (r *ContainerResource) Schema(ctx context.Context, req resource.SchemaReque
 					},
 				},
 			},
+			"networks_advanced": schema.ListNestedBlock{
+				Description: "Networks to attach to the container with per-network settings. The first is attached when the container is created, the others are connected before it starts.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "The name of the network.",
+							Required:    true,
+						},
+						"aliases": schema.SetAttribute{
+							Description: "Network-scoped DNS aliases for the container.",
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+						"ipv4_address": schema.StringAttribute{
+							Description: "Static IPv4 address on the network. Reports the assigned address when not set.",
+							Optional:    true,
+							Computed:    true,
+						},
+						"ipv6_address": schema.StringAttribute{
+							Description: "Static IPv6 address on the network. Reports the assigned address when not set.",
+							Optional:    true,
+							Computed:    true,
+						},
+						"links": schema.ListAttribute{
+							Description: "Legacy links to other containers on the network, in the form container:alias.",
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+						"driver_opts": schema.MapAttribute{
+							Description: "Options for the network driver of this endpoint.",
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+			"upload": schema.ListNestedBlock{
+				Description: "Files to copy into the container after it is created and before it is started. Works against remote daemons, unlike host_path volumes. Changes, including changes to the content of a source file, replace the container.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"file": schema.StringAttribute{
+							Description: "Absolute path of the file inside the container. Missing parent directories are created.",
+							Required:    true,
+						},
+						"content": schema.StringAttribute{
+							Description: "Literal content of the file. Exactly one of content, content_base64 or source must be set.",
+							Optional:    true,
+						},
+						"content_base64": schema.StringAttribute{
+							Description: "Base64 encoded content of the file, for binary files.",
+							Optional:    true,
+						},
+						"source": schema.StringAttribute{
+							Description: "Path of a local file to upload.",
+							Optional:    true,
+						},
+						"permissions": schema.StringAttribute{
+							Description: "Octal file mode (e.g., '0640'). Defaults to 0644, or 0755 when executable is true.",
+							Optional:    true,
+						},
+						"executable": schema.BoolAttribute{
+							Description: "Make the file executable. Ignored when permissions is set.",
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+						},
+						"content_hash": schema.StringAttribute{
+							Description: "SHA256 hash of the uploaded content, used for change detection.",
+							Computed:    true,
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.UseStateForUnknown(),
+							},
+						},
+					},
+				},
+			},
 			"healthcheck": schema.SingleNestedBlock{
 				Description: "Health check configuration.",
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
 				Attributes: map[string]schema.Attribute{
 					"test": schema.ListAttribute{
 						Description: "Command to run to check health.",
@@ -372,7 +638,7 @@ func (r *ContainerResource) Configure(ctx context.Context, req resource.Configur
 		return
 	}
 
-	r.client = providerData.DockerClient
+	r.clients = providerData.DockerClients
 }
 
 func (r *ContainerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -383,6 +649,11 @@ func (r *ContainerResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	containerName := data.Name.ValueString()
 	tflog.Debug(ctx, "Creating Docker container", map[string]interface{}{
 		"name":  containerName,
@@ -513,19 +784,8 @@ func (r *ContainerResource) Create(ctx context.Context, req resource.CreateReque
 	}
 
 	// Restart policy
-	restart := data.Restart.ValueString()
-	if restart != "" {
-		policy := container.RestartPolicy{Name: container.RestartPolicyMode(restart)}
-		if strings.HasPrefix(restart, "on-failure") {
-			parts := strings.Split(restart, ":")
-			if len(parts) == 2 {
-				var maxRetries int
-				fmt.Sscanf(parts[1], "%d", &maxRetries)
-				policy.Name = container.RestartPolicyOnFailure
-				policy.MaximumRetryCount = maxRetries
-			}
-		}
-		hostConfig.RestartPolicy = policy
+	if restart := data.Restart.ValueString(); restart != "" {
+		hostConfig.RestartPolicy = parseRestartPolicy(restart)
 	}
 
 	// Network mode
@@ -617,8 +877,20 @@ func (r *ContainerResource) Create(ctx context.Context, req resource.CreateReque
 		}
 	}
 
+	// The first networks_advanced entry is attached at create time, the others are connected afterwards
+	if len(data.NetworksAdvanced) > 0 {
+		first := data.NetworksAdvanced[0]
+		if networkConfig.EndpointsConfig == nil {
+			networkConfig.EndpointsConfig = make(map[string]*network.EndpointSettings)
+		}
+		networkConfig.EndpointsConfig[first.Name.ValueString()] = first.endpointSettings(ctx, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Create container
-	containerResp, err := r.client.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nil, containerName)
+	containerResp, err := client.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nil, containerName)
 	if err != nil {
 		resp.Diagnostics.AddError("Container Create Error", fmt.Sprintf("Unable to create container %s: %s", containerName, err))
 		return
@@ -627,17 +899,35 @@ func (r *ContainerResource) Create(ctx context.Context, req resource.CreateReque
 	data.ID = types.StringValue(containerResp.ID)
 	data.ContainerID = types.StringValue(containerResp.ID)
 
-	// Start container if must_run is true
-	if data.MustRun.ValueBool() {
-		err = r.client.ContainerStart(ctx, containerResp.ID, container.StartOptions{})
-		if err != nil {
-			resp.Diagnostics.AddError("Container Start Error", fmt.Sprintf("Unable to start container %s: %s", containerName, err))
-			return
-		}
+	if len(data.NetworksAdvanced) > 1 {
+		r.connectNetworks(ctx, client, containerResp.ID, data.NetworksAdvanced[1:], &resp.Diagnostics)
+	}
+
+	// Copy uploads into the container before it starts
+	if !resp.Diagnostics.HasError() {
+		r.uploadFiles(ctx, client, &data, &resp.Diagnostics)
+	}
+
+	// Run the container as a job, or bring it into its desired run state
+	data.Stdout = types.StringNull()
+	data.Stderr = types.StringNull()
+	switch {
+	case resp.Diagnostics.HasError():
+	case data.RunToCompletion.ValueBool():
+		r.runToCompletion(ctx, client, &data, &resp.Diagnostics)
+	default:
+		r.reconcileState(ctx, client, &data, &resp.Diagnostics)
 	}
 
 	// Refresh state with computed values
-	r.readContainerState(ctx, &data, resp)
+	r.readContainerState(ctx, client, &data)
+
+	// A container that failed to start or never became ready is still saved, so that it is tainted and
+	// replaced on the next apply
+	if resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
 
 	tflog.Debug(ctx, "Created Docker container", map[string]interface{}{
 		"name": containerName,
@@ -655,9 +945,14 @@ func (r *ContainerResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	containerID := data.ID.ValueString()
 
-	containerJSON, err := r.client.ContainerInspect(ctx, containerID)
+	containerJSON, err := client.ContainerInspect(ctx, containerID)
 	if err != nil {
 		if strings.Contains(err.Error(), "No such container") || strings.Contains(err.Error(), "not found") {
 			resp.State.RemoveResource(ctx)
@@ -671,21 +966,35 @@ func (r *ContainerResource) Read(ctx context.Context, req resource.ReadRequest,
 	data.Image = types.StringValue(containerJSON.Config.Image)
 	data.ContainerID = types.StringValue(containerJSON.ID)
 
-	// Network info
-	if containerJSON.NetworkSettings != nil {
-		if containerJSON.NetworkSettings.IPAddress != "" {
-			data.IPAddress = types.StringValue(containerJSON.NetworkSettings.IPAddress)
-		}
-		if containerJSON.NetworkSettings.Gateway != "" {
-			data.Gateway = types.StringValue(containerJSON.NetworkSettings.Gateway)
-		}
+	// Settings that are not stored on the container are defaulted on import
+	if data.Remove.IsNull() {
+		data.Remove = types.BoolValue(true)
+	}
+	if data.MustRun.IsNull() {
+		data.MustRun = types.BoolValue(containerJSON.State != nil && containerJSON.State.Running)
+	}
+	data.State = types.StringValue(containerRunState(containerJSON.State))
+	if data.RunToCompletion.IsNull() {
+		data.RunToCompletion = types.BoolValue(false)
+	}
+	if data.CompletionTimeout.IsNull() {
+		data.CompletionTimeout = types.StringValue("5m")
+	}
+	if data.Wait.IsNull() {
+		data.Wait = types.BoolValue(false)
+	}
+	if data.WaitTimeout.IsNull() {
+		data.WaitTimeout = types.StringValue("60s")
 	}
 
-	// Exit code
-	if containerJSON.State != nil {
-		data.ExitCode = types.Int64Value(int64(containerJSON.State.ExitCode))
+	defaults := r.readImageDefaults(ctx, client, containerJSON.Image)
+	mapContainerConfig(ctx, containerJSON, defaults, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	setContainerRuntimeState(containerJSON, &data)
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -697,8 +1006,107 @@ func (r *ContainerResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	// Most container changes require recreation
-	// Only save state for non-recreating updates
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state ContainerResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Attributes not handled here are marked RequiresReplace in the schema
+	containerID := state.ID.ValueString()
+	data.ID = state.ID
+	data.ContainerID = state.ContainerID
+
+	// Resource limits and restart policy can be changed on a live container; removing a limit replaces it instead
+	resourcesChanged := !data.Memory.Equal(state.Memory) ||
+		!data.MemorySwap.Equal(state.MemorySwap) ||
+		!data.CPUShares.Equal(state.CPUShares) ||
+		!data.CPUPeriod.Equal(state.CPUPeriod) ||
+		!data.CPUQuota.Equal(state.CPUQuota)
+	restartChanged := !data.Restart.Equal(state.Restart)
+
+	if resourcesChanged || restartChanged {
+		updateConfig := container.UpdateConfig{
+			Resources: container.Resources{
+				Memory:     data.Memory.ValueInt64(),
+				MemorySwap: data.MemorySwap.ValueInt64(),
+				CPUShares:  data.CPUShares.ValueInt64(),
+				CPUPeriod:  data.CPUPeriod.ValueInt64(),
+				CPUQuota:   data.CPUQuota.ValueInt64(),
+			},
+		}
+		if restartChanged {
+			updateConfig.RestartPolicy = parseRestartPolicy(data.Restart.ValueString())
+		}
+
+		tflog.Debug(ctx, "Updating Docker container", map[string]interface{}{
+			"id": containerID,
+		})
+
+		updateResp, err := client.ContainerUpdate(ctx, containerID, updateConfig)
+		if err != nil {
+			resp.Diagnostics.AddError("Container Update Error", fmt.Sprintf("Unable to update container %s: %s", containerID, err))
+			return
+		}
+		for _, warning := range updateResp.Warnings {
+			resp.Diagnostics.AddWarning("Container Update Warning", warning)
+		}
+	}
+
+	// Networks can be connected and disconnected on a live container. The networks set and networks_advanced
+	// are diffed as one, so that a network moved from one to the other is disconnected before it is connected.
+	oldNetworks := containerNetworks(ctx, &state, &resp.Diagnostics)
+	newNetworks := containerNetworks(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	oldEndpoints := make(map[string]NetworkAdvancedModel, len(oldNetworks))
+	for _, n := range oldNetworks {
+		oldEndpoints[n.Name.ValueString()] = n
+	}
+	newEndpoints := make(map[string]NetworkAdvancedModel, len(newNetworks))
+	for _, n := range newNetworks {
+		newEndpoints[n.Name.ValueString()] = n
+	}
+
+	// Networks that are gone or whose settings changed are disconnected, and new or changed ones connected
+	for _, old := range oldNetworks {
+		name := old.Name.ValueString()
+		if n, ok := newEndpoints[name]; ok && n.sameEndpoint(old) {
+			continue
+		}
+		if err := client.NetworkDisconnect(ctx, name, containerID, false); err != nil {
+			resp.Diagnostics.AddError("Network Disconnect Error", fmt.Sprintf("Unable to disconnect container %s from network %s: %s", containerID, name, err))
+			return
+		}
+	}
+
+	var connect []NetworkAdvancedModel
+	for _, n := range newNetworks {
+		if old, ok := oldEndpoints[n.Name.ValueString()]; ok && n.sameEndpoint(old) {
+			continue
+		}
+		connect = append(connect, n)
+	}
+	r.connectNetworks(ctx, client, containerID, connect, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Start, stop, pause or unpause the container if it is not in its desired run state
+	r.reconcileState(ctx, client, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Refresh state with computed values
+	r.readContainerState(ctx, client, &data)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -711,6 +1119,11 @@ func (r *ContainerResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if !data.Remove.ValueBool() {
 		tflog.Debug(ctx, "Not removing container as configured", map[string]interface{}{
 			"name": data.Name.ValueString(),
@@ -726,7 +1139,7 @@ func (r *ContainerResource) Delete(ctx context.Context, req resource.DeleteReque
 
 	// Stop container first
 	timeout := 30
-	err := r.client.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeout})
+	err := client.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeout})
 	if err != nil && !strings.Contains(err.Error(), "is not running") && !strings.Contains(err.Error(), "No such container") {
 		resp.Diagnostics.AddError("Container Stop Error", fmt.Sprintf("Unable to stop container %s: %s", containerID, err))
 		return
@@ -737,7 +1150,7 @@ func (r *ContainerResource) Delete(ctx context.Context, req resource.DeleteReque
 	})
 
 	// Remove container
-	err = r.client.ContainerRemove(ctx, containerID, container.RemoveOptions{
+	err = client.ContainerRemove(ctx, containerID, container.RemoveOptions{
 		Force:         true,
 		RemoveVolumes: false,
 	})
@@ -754,34 +1167,1015 @@ func (r *ContainerResource) Delete(ctx context.Context, req resource.DeleteReque
 	})
 }
 
-func (r *ContainerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+// requiresReplaceIfLimitRemoved replaces the container when a resource limit is removed or set to zero. The daemon
+// treats zero in a container update as unchanged, so a live container cannot go back to having no limit.
+func requiresReplaceIfLimitRemoved() planmodifier.Int64 {
+	return int64planmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.Int64Request, resp *int64planmodifier.RequiresReplaceIfFuncResponse) {
+		resp.RequiresReplace = req.StateValue.ValueInt64() != 0 && !req.PlanValue.IsUnknown() && req.PlanValue.ValueInt64() == 0
+	}, "Removing the limit replaces the container.", "Removing the limit replaces the container.")
 }
 
-func (r *ContainerResource) readContainerState(ctx context.Context, data *ContainerResourceModel, resp *resource.CreateResponse) {
-	containerJSON, err := r.client.ContainerInspect(ctx, data.ID.ValueString())
-	if err != nil {
+// ModifyPlan plans the run state and the content hashes of uploads
+func (r *ContainerResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
 		return
 	}
 
-	// Network info
-	if containerJSON.NetworkSettings != nil {
-		if containerJSON.NetworkSettings.IPAddress != "" {
-			data.IPAddress = types.StringValue(containerJSON.NetworkSettings.IPAddress)
-		} else {
-			data.IPAddress = types.StringNull()
+	r.planState(ctx, req, resp)
+	r.planNetworks(ctx, req, resp)
+	r.planUploads(ctx, req, resp)
+}
+
+// planState validates state and defaults it from must_run and run_to_completion. The default is set on every
+// plan, rather than carried over from state, so that a container that is no longer in its desired run state
+// is planned to be brought back.
+func (r *ContainerResource) planState(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var configState types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("state"), &configState)...)
+	if resp.Diagnostics.HasError() || configState.IsUnknown() {
+		return
+	}
+	if !configState.IsNull() {
+		switch configState.ValueString() {
+		case containerStateRunning, containerStateStopped, containerStatePaused:
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("state"),
+				"Invalid Container State",
+				fmt.Sprintf("state must be one of running, stopped or paused, got %q.", configState.ValueString()),
+			)
 		}
-		if containerJSON.NetworkSettings.Gateway != "" {
-			data.Gateway = types.StringValue(containerJSON.NetworkSettings.Gateway)
-		} else {
-			data.Gateway = types.StringNull()
+		return
+	}
+
+	var mustRun, runToCompletion types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("must_run"), &mustRun)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("run_to_completion"), &runToCompletion)...)
+	if resp.Diagnostics.HasError() || mustRun.IsUnknown() || runToCompletion.IsUnknown() {
+		return
+	}
+
+	// A job container has exited once it has been created
+	desired := containerStateRunning
+	if !mustRun.ValueBool() || runToCompletion.ValueBool() {
+		desired = containerStateStopped
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("state"), desired)...)
+}
+
+// planNetworks rejects a network listed both in the networks set and in networks_advanced, which would
+// attach the container to it twice
+func (r *ContainerResource) planNetworks(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var networks types.Set
+	var advanced []NetworkAdvancedModel
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("networks"), &networks)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("networks_advanced"), &advanced)...)
+	if resp.Diagnostics.HasError() || networks.IsNull() || networks.IsUnknown() {
+		return
+	}
+
+	var names []types.String
+	resp.Diagnostics.Append(networks.ElementsAs(ctx, &names, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for i, n := range advanced {
+		if n.Name.IsUnknown() || !slices.Contains(names, n.Name) {
+			continue
 		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root("networks_advanced").AtListIndex(i).AtName("name"),
+			"Conflicting Container Networks",
+			fmt.Sprintf("Network %s is listed in both networks and networks_advanced. List it in only one of them.", n.Name.ValueString()),
+		)
+	}
+}
+
+// planUploads hashes the content of every upload and decides whether the uploads replace the container. Any
+// change to the upload blocks, or to the content of a source file on disk, requires replacement; changes to
+// other attributes leave the uploads alone so that they can still be updated in place.
+func (r *ContainerResource) planUploads(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var uploads []UploadModel
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("upload"), &uploads)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Exit code
-	if containerJSON.State != nil {
-		data.ExitCode = types.Int64Value(int64(containerJSON.State.ExitCode))
+	var priorUploads []UploadModel
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("upload"), &priorUploads)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	replace := len(uploads) != len(priorUploads)
+	for i, upload := range uploads {
+		hashPath := path.Root("upload").AtListIndex(i).AtName("content_hash")
+
+		if upload.File.IsUnknown() || upload.Content.IsUnknown() || upload.ContentBase64.IsUnknown() ||
+			upload.Source.IsUnknown() || upload.Permissions.IsUnknown() || upload.Executable.IsUnknown() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, hashPath, types.StringUnknown())...)
+			replace = true
+			continue
+		}
+
+		file, err := upload.uploadFile()
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("upload").AtListIndex(i), "Invalid Upload", err.Error())
+			continue
+		}
+
+		contentHash := types.StringValue(fmt.Sprintf("sha256:%x", sha256.Sum256(file.Content)))
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, hashPath, contentHash)...)
+
+		if i >= len(priorUploads) {
+			continue
+		}
+		prior := priorUploads[i]
+		if !upload.sameConfig(prior) || (!prior.ContentHash.IsNull() && !prior.ContentHash.Equal(contentHash)) {
+			replace = true
+		}
+	}
+
+	if replace && !req.State.Raw.IsNull() {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("upload"))
+	}
+}
+
+// sameConfig reports whether two upload blocks are configured the same, ignoring the computed content hash
+func (u UploadModel) sameConfig(other UploadModel) bool {
+	return u.File.Equal(other.File) &&
+		u.Content.Equal(other.Content) &&
+		u.ContentBase64.Equal(other.ContentBase64) &&
+		u.Source.Equal(other.Source) &&
+		u.Permissions.Equal(other.Permissions) &&
+		u.Executable.Equal(other.Executable)
+}
+
+// uploadFile resolves the content and mode of an upload block
+func (u UploadModel) uploadFile() (docker.UploadFile, error) {
+	file := docker.UploadFile{Path: u.File.ValueString(), Mode: 0o644}
+	if !strings.HasPrefix(file.Path, "/") {
+		return file, fmt.Errorf("file must be an absolute path inside the container, got %q", file.Path)
+	}
+
+	sources := 0
+	for _, value := range []types.String{u.Content, u.ContentBase64, u.Source} {
+		if !value.IsNull() {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return file, fmt.Errorf("exactly one of content, content_base64 or source must be set for %s", file.Path)
+	}
+
+	var err error
+	switch {
+	case !u.Content.IsNull():
+		file.Content = []byte(u.Content.ValueString())
+	case !u.ContentBase64.IsNull():
+		file.Content, err = base64.StdEncoding.DecodeString(u.ContentBase64.ValueString())
+		if err != nil {
+			return file, fmt.Errorf("invalid content_base64 for %s: %w", file.Path, err)
+		}
+	default:
+		file.Content, err = os.ReadFile(u.Source.ValueString())
+		if err != nil {
+			return file, fmt.Errorf("failed to read source for %s: %w", file.Path, err)
+		}
+	}
+
+	switch {
+	case !u.Permissions.IsNull():
+		file.Mode, err = strconv.ParseInt(u.Permissions.ValueString(), 8, 32)
+		if err != nil || file.Mode < 0 || file.Mode > 0o7777 {
+			return file, fmt.Errorf("permissions for %s must be an octal mode such as 0644, got %q", file.Path, u.Permissions.ValueString())
+		}
+	case u.Executable.ValueBool():
+		file.Mode = 0o755
+	}
+
+	return file, nil
+}
+
+// uploadFiles copies the upload blocks into the created container before it is started
+func (r *ContainerResource) uploadFiles(ctx context.Context, client *docker.Client, data *ContainerResourceModel, diagnostics *diag.Diagnostics) {
+	if len(data.Upload) == 0 {
+		return
+	}
+	containerID := data.ID.ValueString()
+
+	files := make([]docker.UploadFile, 0, len(data.Upload))
+	for i, upload := range data.Upload {
+		file, err := upload.uploadFile()
+		if err != nil {
+			diagnostics.AddAttributeError(path.Root("upload").AtListIndex(i), "Invalid Upload", err.Error())
+			return
+		}
+		files = append(files, file)
+
+		// The hash is normally known from the plan; compute it if it was not
+		if upload.ContentHash.IsUnknown() || upload.ContentHash.IsNull() {
+			data.Upload[i].ContentHash = types.StringValue(fmt.Sprintf("sha256:%x", sha256.Sum256(file.Content)))
+		}
+	}
+
+	archive, err := docker.UploadArchive(files)
+	if err != nil {
+		diagnostics.AddError("Container Upload Error", fmt.Sprintf("Unable to prepare uploads for container %s: %s", containerID, err))
+		return
+	}
+
+	tflog.Debug(ctx, "Uploading files into Docker container", map[string]interface{}{
+		"id":    containerID,
+		"files": len(files),
+	})
+
+	if err := client.CopyToContainer(ctx, containerID, "/", archive, container.CopyToContainerOptions{}); err != nil {
+		diagnostics.AddError("Container Upload Error", fmt.Sprintf("Unable to upload files into container %s: %s", containerID, err))
+	}
+}
+
+func (r *ContainerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	_, id := importHostID(ctx, r.clients, req.ID, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// containerNetworks returns the networks of the networks set, with default endpoint settings, followed by
+// the networks_advanced entries
+func containerNetworks(ctx context.Context, data *ContainerResourceModel, diagnostics *diag.Diagnostics) []NetworkAdvancedModel {
+	var names []string
+	if !data.Networks.IsNull() && !data.Networks.IsUnknown() {
+		diagnostics.Append(data.Networks.ElementsAs(ctx, &names, false)...)
+	}
+
+	networks := make([]NetworkAdvancedModel, 0, len(names)+len(data.NetworksAdvanced))
+	for _, name := range names {
+		networks = append(networks, NetworkAdvancedModel{
+			Name:        types.StringValue(name),
+			Aliases:     types.SetNull(types.StringType),
+			IPv4Address: types.StringNull(),
+			IPv6Address: types.StringNull(),
+			Links:       types.ListNull(types.StringType),
+			DriverOpts:  types.MapNull(types.StringType),
+		})
+	}
+	return append(networks, data.NetworksAdvanced...)
+}
+
+// connectNetworks connects the container to networks with the endpoint settings of their entries
+func (r *ContainerResource) connectNetworks(ctx context.Context, client *docker.Client, containerID string, networks []NetworkAdvancedModel, diagnostics *diag.Diagnostics) {
+	for _, n := range networks {
+		name := n.Name.ValueString()
+		settings := n.endpointSettings(ctx, diagnostics)
+		if diagnostics.HasError() {
+			return
+		}
+
+		if err := client.NetworkConnect(ctx, name, containerID, settings); err != nil {
+			diagnostics.AddError("Network Connect Error", fmt.Sprintf("Unable to connect container %s to network %s: %s", containerID, name, err))
+			return
+		}
+	}
+}
+
+// endpointSettings converts a networks_advanced entry into the endpoint settings of the network
+func (n NetworkAdvancedModel) endpointSettings(ctx context.Context, diagnostics *diag.Diagnostics) *network.EndpointSettings {
+	settings := &network.EndpointSettings{}
+
+	if !n.Aliases.IsNull() {
+		diagnostics.Append(n.Aliases.ElementsAs(ctx, &settings.Aliases, false)...)
+	}
+	if !n.Links.IsNull() {
+		diagnostics.Append(n.Links.ElementsAs(ctx, &settings.Links, false)...)
+	}
+	if !n.DriverOpts.IsNull() {
+		diagnostics.Append(n.DriverOpts.ElementsAs(ctx, &settings.DriverOpts, false)...)
+	}
+
+	// Unknown addresses are left to the network's IPAM driver
+	ipv4, ipv6 := n.IPv4Address.ValueString(), n.IPv6Address.ValueString()
+	if ipv4 != "" || ipv6 != "" {
+		settings.IPAMConfig = &network.EndpointIPAMConfig{
+			IPv4Address: ipv4,
+			IPv6Address: ipv6,
+		}
+	}
+
+	return settings
+}
+
+// sameEndpoint reports whether two networks_advanced entries have the same settings. Addresses are only
+// compared when they are known, which they are when configured.
+func (n NetworkAdvancedModel) sameEndpoint(other NetworkAdvancedModel) bool {
+	if !n.IPv4Address.IsUnknown() && !n.IPv4Address.Equal(other.IPv4Address) {
+		return false
+	}
+	if !n.IPv6Address.IsUnknown() && !n.IPv6Address.Equal(other.IPv6Address) {
+		return false
+	}
+	return n.Aliases.Equal(other.Aliases) && n.Links.Equal(other.Links) && n.DriverOpts.Equal(other.DriverOpts)
+}
+
+// endpointAddress returns the address the daemon assigned on a network, keeping the known prior value while
+// the container has none, such as when it is stopped
+func endpointAddress(assigned string, prior types.String) types.String {
+	if assigned != "" {
+		return types.StringValue(assigned)
+	}
+	if prior.IsUnknown() {
+		return types.StringNull()
+	}
+	return prior
+}
+
+// runToCompletion starts the container, waits for it to exit and captures its output. The exit code must be
+// one of allowed_exit_codes.
+func (r *ContainerResource) runToCompletion(ctx context.Context, client *docker.Client, data *ContainerResourceModel, diagnostics *diag.Diagnostics) {
+	containerID := data.ID.ValueString()
+
+	timeout, err := time.ParseDuration(data.CompletionTimeout.ValueString())
+	if err != nil {
+		diagnostics.AddAttributeError(path.Root("completion_timeout"), "Invalid Completion Timeout", fmt.Sprintf("Failed to parse completion_timeout: %s", err))
+		return
+	}
+
+	allowedExitCodes := []int64{0}
+	if !data.AllowedExitCodes.IsNull() {
+		diagnostics.Append(data.AllowedExitCodes.ElementsAs(ctx, &allowedExitCodes, false)...)
+		if diagnostics.HasError() {
+			return
+		}
+	}
+
+	tflog.Debug(ctx, "Running Docker container to completion", map[string]interface{}{
+		"id":      containerID,
+		"timeout": timeout.String(),
+	})
+
+	// Wait before starting, so that a container that exits right away is not missed
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	waitCh, errCh := client.ContainerWait(waitCtx, containerID, container.WaitConditionNextExit)
+
+	if err := client.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
+		diagnostics.AddError("Container Start Error", fmt.Sprintf("Unable to start container %s: %s", containerID, err))
+		return
+	}
+
+	var exitCode int64
+	var waitErr error
+	select {
+	case result := <-waitCh:
+		exitCode = result.StatusCode
+		if result.Error != nil {
+			waitErr = errors.New(result.Error.Message)
+		}
+	case err := <-errCh:
+		waitErr = err
+		if waitCtx.Err() == context.DeadlineExceeded {
+			waitErr = fmt.Errorf("did not exit within %s", timeout)
+
+			// Stop the job, so that it does not keep running next to the one the next apply starts
+			stopTimeout := 30
+			if err := client.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &stopTimeout}); err != nil {
+				diagnostics.AddError("Container Stop Error", fmt.Sprintf("Unable to stop container %s after completion_timeout: %s", containerID, err))
+			}
+		}
+	}
+
+	stdout, stderr, err := r.containerOutput(ctx, client, containerID)
+	if err != nil {
+		diagnostics.AddError("Container Logs Error", fmt.Sprintf("Unable to read output of container %s: %s", containerID, err))
+		return
+	}
+	data.Stdout = types.StringValue(stdout)
+	data.Stderr = types.StringValue(stderr)
+
+	if waitErr != nil {
+		diagnostics.AddError("Container Run Failed", fmt.Sprintf("Container %s %s.%s", containerID, waitErr, outputTail(stdout, stderr)))
+		return
+	}
+
+	data.ExitCode = types.Int64Value(exitCode)
+	if !slices.Contains(allowedExitCodes, exitCode) {
+		diagnostics.AddError(
+			"Container Run Failed",
+			fmt.Sprintf("Container %s exited with code %d, allowed exit codes are %v.%s", containerID, exitCode, allowedExitCodes, outputTail(stdout, stderr)),
+		)
+	}
+}
+
+// containerOutput reads the complete stdout and stderr of a container, demultiplexed the same way as the
+// docker_logs data source. Containers with a TTY have a single stream, which is returned as stdout.
+func (r *ContainerResource) containerOutput(ctx context.Context, client *docker.Client, containerID string) (string, string, error) {
+	containerJSON, err := client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", "", err
+	}
+
+	logs, err := client.ContainerLogs(ctx, containerID, container.LogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return "", "", err
+	}
+	defer logs.Close()
+
+	var stdout, stderr bytes.Buffer
+	if containerJSON.Config != nil && containerJSON.Config.Tty {
+		_, err = io.Copy(&stdout, logs)
+	} else {
+		err = docker.DemuxLogs(logs, &stdout, &stderr)
+	}
+	return stdout.String(), stderr.String(), err
+}
+
+// outputTail formats the last lines of a job container's output for a diagnostic, preferring stderr
+func outputTail(stdout, stderr string) string {
+	output := strings.TrimRight(stderr, "\n")
+	if output == "" {
+		output = strings.TrimRight(stdout, "\n")
+	}
+	if output == "" {
+		return ""
+	}
+
+	lines := strings.Split(output, "\n")
+	if len(lines) > outputTailLines {
+		lines = lines[len(lines)-outputTailLines:]
+	}
+	return "\n\nLast output:\n" + strings.Join(lines, "\n")
+}
+
+// reconcileState starts, stops, pauses or unpauses the container to match the desired state, waiting for it
+// to become ready when wait is set and it had to be started
+func (r *ContainerResource) reconcileState(ctx context.Context, client *docker.Client, data *ContainerResourceModel, diagnostics *diag.Diagnostics) {
+	containerID := data.ID.ValueString()
+	desired := data.State.ValueString()
+
+	containerJSON, err := client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		diagnostics.AddError("Container Read Error", fmt.Sprintf("Unable to inspect container %s: %s", containerID, err))
+		return
+	}
+	current := containerRunState(containerJSON.State)
+	if current == desired {
+		return
+	}
+
+	tflog.Debug(ctx, "Changing Docker container run state", map[string]interface{}{
+		"id":   containerID,
+		"from": current,
+		"to":   desired,
+	})
+
+	// A paused container is unpaused first, whether it is to run or to stop
+	if current == containerStatePaused {
+		if err := client.ContainerUnpause(ctx, containerID); err != nil {
+			diagnostics.AddError("Container Unpause Error", fmt.Sprintf("Unable to unpause container %s: %s", containerID, err))
+			return
+		}
+		current = containerStateRunning
+	}
+
+	if desired == containerStateStopped {
+		if current == containerStateRunning {
+			timeout := 30
+			if err := client.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeout}); err != nil {
+				diagnostics.AddError("Container Stop Error", fmt.Sprintf("Unable to stop container %s: %s", containerID, err))
+			}
+		}
+		return
+	}
+
+	if current == containerStateStopped {
+		if err := client.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
+			diagnostics.AddError("Container Start Error", fmt.Sprintf("Unable to start container %s: %s", containerID, err))
+			return
+		}
+
+		if data.Wait.ValueBool() {
+			r.waitForContainer(ctx, client, data, diagnostics)
+			if diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	if desired == containerStatePaused {
+		if err := client.ContainerPause(ctx, containerID); err != nil {
+			diagnostics.AddError("Container Pause Error", fmt.Sprintf("Unable to pause container %s: %s", containerID, err))
+		}
+	}
+}
+
+// containerRunState reduces the daemon's container status to running, stopped or paused. A restarting
+// container counts as running, so that a restart policy at work is not reported as drift.
+func containerRunState(state *container.State) string {
+	if state == nil {
+		return containerStateStopped
+	}
+	switch {
+	case state.Paused:
+		return containerStatePaused
+	case state.Running, state.Restarting:
+		return containerStateRunning
+	default:
+		return containerStateStopped
+	}
+}
+
+// waitForContainer polls the started container until it is healthy, or running when it has no healthcheck
+func (r *ContainerResource) waitForContainer(ctx context.Context, client *docker.Client, data *ContainerResourceModel, diagnostics *diag.Diagnostics) {
+	containerID := data.ID.ValueString()
+
+	timeout, err := time.ParseDuration(data.WaitTimeout.ValueString())
+	if err != nil {
+		diagnostics.AddAttributeError(path.Root("wait_timeout"), "Invalid Wait Timeout", fmt.Sprintf("Failed to parse wait_timeout: %s", err))
+		return
+	}
+
+	tflog.Debug(ctx, "Waiting for Docker container to become ready", map[string]interface{}{
+		"id":      containerID,
+		"timeout": timeout.String(),
+	})
+
+	delay := time.Second
+	deadline := time.Now().Add(timeout)
+	for {
+		containerJSON, err := client.ContainerInspect(ctx, containerID)
+		if err != nil {
+			diagnostics.AddError("Container Read Error", fmt.Sprintf("Unable to inspect container %s: %s", containerID, err))
+			return
+		}
+
+		var health *container.Health
+		state := containerJSON.State
+		if state != nil {
+			health = state.Health
+
+			// Health is set whenever a healthcheck applies, whether configured here or inherited from the image
+			if health != nil {
+				switch health.Status {
+				case container.Healthy:
+					return
+				case container.Unhealthy:
+					diagnostics.AddError("Container Not Ready", fmt.Sprintf("Container %s is unhealthy.%s", containerID, healthcheckLog(health)))
+					return
+				}
+			} else if state.Running {
+				return
+			}
+
+			if !state.Running && !state.Restarting && state.Status != string(container.StateCreated) {
+				diagnostics.AddError("Container Not Ready", fmt.Sprintf("Container %s exited with code %d before becoming ready.%s", containerID, state.ExitCode, healthcheckLog(health)))
+				return
+			}
+		}
+
+		if time.Now().After(deadline) {
+			condition := "running"
+			if health != nil {
+				condition = "healthy"
+			}
+			diagnostics.AddError("Container Not Ready", fmt.Sprintf("Container %s did not become %s within %s.%s", containerID, condition, timeout, healthcheckLog(health)))
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			diagnostics.AddError("Container Not Ready", fmt.Sprintf("Stopped waiting for container %s: %s", containerID, ctx.Err()))
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// healthcheckLog formats the most recent healthcheck results for a diagnostic
+func healthcheckLog(health *container.Health) string {
+	if health == nil || len(health.Log) == 0 {
+		return ""
+	}
+
+	entries := health.Log
+	if len(entries) > healthcheckLogEntries {
+		entries = entries[len(entries)-healthcheckLogEntries:]
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\nLast healthcheck results:")
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "\n- exit code %d: %s", entry.ExitCode, strings.TrimSpace(entry.Output))
+	}
+	return b.String()
+}
+
+func (r *ContainerResource) readContainerState(ctx context.Context, client *docker.Client, data *ContainerResourceModel) {
+	containerJSON, err := client.ContainerInspect(ctx, data.ID.ValueString())
+	if err != nil {
+		return
+	}
+
+	setContainerRuntimeState(containerJSON, data)
+}
+
+// setContainerRuntimeState sets the computed attributes that reflect the running container
+func setContainerRuntimeState(containerJSON container.InspectResponse, data *ContainerResourceModel) {
+	// Network info
+	if containerJSON.NetworkSettings != nil {
+		if containerJSON.NetworkSettings.IPAddress != "" {
+			data.IPAddress = types.StringValue(containerJSON.NetworkSettings.IPAddress)
+		} else {
+			data.IPAddress = types.StringNull()
+		}
+		if containerJSON.NetworkSettings.Gateway != "" {
+			data.Gateway = types.StringValue(containerJSON.NetworkSettings.Gateway)
+		} else {
+			data.Gateway = types.StringNull()
+		}
+	}
+
+	// Addresses on networks_advanced entries
+	for i := range data.NetworksAdvanced {
+		n := &data.NetworksAdvanced[i]
+
+		var endpoint *network.EndpointSettings
+		if containerJSON.NetworkSettings != nil {
+			endpoint = containerJSON.NetworkSettings.Networks[n.Name.ValueString()]
+		}
+		if endpoint == nil {
+			endpoint = &network.EndpointSettings{}
+		}
+		n.IPv4Address = endpointAddress(endpoint.IPAddress, n.IPv4Address)
+		n.IPv6Address = endpointAddress(endpoint.GlobalIPv6Address, n.IPv6Address)
+	}
+
+	// Exit code
+	if containerJSON.State != nil {
+		data.ExitCode = types.Int64Value(int64(containerJSON.State.ExitCode))
+	}
+}
+
+// imageDefaults holds the configuration a container inherits from its image when it does not override it
+type imageDefaults struct {
+	Env         map[string]string
+	Labels      map[string]string
+	Cmd         []string
+	Entrypoint  []string
+	User        string
+	WorkingDir  string
+	Volumes     map[string]struct{}
+	Healthcheck *container.HealthConfig
+}
+
+// readImageDefaults inspects the container's image. If the image is gone nothing is treated as inherited.
+func (r *ContainerResource) readImageDefaults(ctx context.Context, client *docker.Client, imageID string) imageDefaults {
+	imageInspect, err := client.ImageInspect(ctx, imageID)
+	if err != nil || imageInspect.Config == nil {
+		tflog.Debug(ctx, "Unable to inspect container image, not filtering inherited settings", map[string]interface{}{
+			"image": imageID,
+		})
+		return imageDefaults{}
+	}
+
+	config := imageInspect.Config
+	return imageDefaults{
+		Env:         envToMap(config.Env),
+		Labels:      config.Labels,
+		Cmd:         config.Cmd,
+		Entrypoint:  config.Entrypoint,
+		User:        config.User,
+		WorkingDir:  config.WorkingDir,
+		Volumes:     config.Volumes,
+		Healthcheck: config.Healthcheck,
+	}
+}
+
+// mapContainerConfig maps the inspected container configuration onto the model. Values inherited from the
+// image or filled in by the daemon are left out unless they were already tracked in state.
+func mapContainerConfig(ctx context.Context, containerJSON container.InspectResponse, defaults imageDefaults, data *ContainerResourceModel, diagnostics *diag.Diagnostics) {
+	config := containerJSON.Config
+	hostConfig := containerJSON.HostConfig
+	if config == nil || hostConfig == nil {
+		return
+	}
+
+	// Command and entrypoint
+	data.Command = inheritedStringList(ctx, config.Cmd, defaults.Cmd, data.Command, diagnostics)
+	data.Entrypoint = inheritedStringList(ctx, config.Entrypoint, defaults.Entrypoint, data.Entrypoint, diagnostics)
+
+	// Environment variables and labels
+	var priorEnv, priorLabels map[string]string
+	if !data.Env.IsNull() {
+		diagnostics.Append(data.Env.ElementsAs(ctx, &priorEnv, false)...)
+	}
+	if !data.Labels.IsNull() {
+		diagnostics.Append(data.Labels.ElementsAs(ctx, &priorLabels, false)...)
+	}
+	if diagnostics.HasError() {
+		return
+	}
+	data.Env = stringMapOrNull(ctx, withoutInherited(envToMap(config.Env), defaults.Env, priorEnv), diagnostics)
+	data.Labels = stringMapOrNull(ctx, withoutInherited(config.Labels, defaults.Labels, priorLabels), diagnostics)
+
+	// The daemon uses the short container ID as hostname, or the host's when sharing its namespace
+	defaultHostname := containerJSON.ID
+	if len(defaultHostname) > 12 {
+		defaultHostname = defaultHostname[:12]
+	}
+	if hostConfig.NetworkMode.IsHost() || hostConfig.NetworkMode.IsContainer() {
+		defaultHostname = config.Hostname
+	}
+	data.Hostname = inheritedString(config.Hostname, defaultHostname, data.Hostname)
+	data.Domainname = inheritedString(config.Domainname, "", data.Domainname)
+	data.User = inheritedString(config.User, defaults.User, data.User)
+	data.WorkingDir = inheritedString(config.WorkingDir, defaults.WorkingDir, data.WorkingDir)
+
+	data.Restart = types.StringValue(formatRestartPolicy(hostConfig.RestartPolicy))
+	data.Privileged = types.BoolValue(hostConfig.Privileged)
+	data.Tty = types.BoolValue(config.Tty)
+	data.StdinOpen = types.BoolValue(config.OpenStdin)
+
+	// Networks
+	var priorNetworks []string
+	if !data.Networks.IsNull() {
+		diagnostics.Append(data.Networks.ElementsAs(ctx, &priorNetworks, false)...)
+		if diagnostics.HasError() {
+			return
+		}
+	}
+
+	networkMode := string(hostConfig.NetworkMode)
+	if data.NetworkMode.IsNull() && (networkMode == "" || hostConfig.NetworkMode.IsDefault() ||
+		hostConfig.NetworkMode.IsBridge() || slices.Contains(priorNetworks, networkMode)) {
+		data.NetworkMode = types.StringNull()
+	} else {
+		data.NetworkMode = types.StringValue(networkMode)
+	}
+
+	// networks_advanced entries the container was disconnected from are dropped
+	var attachedAdvanced []NetworkAdvancedModel
+	advancedNames := make(map[string]bool, len(data.NetworksAdvanced))
+	for _, n := range data.NetworksAdvanced {
+		if containerJSON.NetworkSettings == nil || containerJSON.NetworkSettings.Networks[n.Name.ValueString()] == nil {
+			continue
+		}
+		attachedAdvanced = append(attachedAdvanced, n)
+		advancedNames[n.Name.ValueString()] = true
+	}
+	if data.NetworksAdvanced != nil {
+		data.NetworksAdvanced = attachedAdvanced
+	}
+
+	// The network implied by the network mode is only tracked if it was listed explicitly, and networks
+	// managed through networks_advanced are left out
+	var networks []string
+	if containerJSON.NetworkSettings != nil {
+		impliedNetwork := hostConfig.NetworkMode.NetworkName()
+		for name := range containerJSON.NetworkSettings.Networks {
+			if name == impliedNetwork && !slices.Contains(priorNetworks, name) {
+				continue
+			}
+			if advancedNames[name] {
+				continue
+			}
+			networks = append(networks, name)
+		}
+	}
+	if len(networks) == 0 && data.Networks.IsNull() {
+		data.Networks = types.SetNull(types.StringType)
+	} else {
+		networksValue, diags := types.SetValueFrom(ctx, types.StringType, networks)
+		diagnostics.Append(diags...)
+		data.Networks = networksValue
+	}
+
+	// DNS and hosts
+	data.DNS = stringListOrNull(ctx, hostConfig.DNS, diagnostics)
+	data.DNSSearch = stringListOrNull(ctx, hostConfig.DNSSearch, diagnostics)
+	data.ExtraHosts = stringListOrNull(ctx, hostConfig.ExtraHosts, diagnostics)
+
+	// Resource limits. The daemon doubles memory for swap when only memory is set.
+	data.Memory = types.Int64Value(hostConfig.Memory)
+	if hostConfig.Memory > 0 && hostConfig.MemorySwap == 2*hostConfig.Memory && data.MemorySwap.ValueInt64() == 0 {
+		data.MemorySwap = types.Int64Value(0)
+	} else {
+		data.MemorySwap = types.Int64Value(hostConfig.MemorySwap)
+	}
+	data.CPUShares = types.Int64Value(hostConfig.CPUShares)
+	data.CPUPeriod = types.Int64Value(hostConfig.CPUPeriod)
+	data.CPUQuota = types.Int64Value(hostConfig.CPUQuota)
+
+	// Port bindings
+	var ports []PortModel
+	for natPort, bindings := range hostConfig.PortBindings {
+		for _, binding := range bindings {
+			port := PortModel{
+				Internal: types.Int64Value(int64(natPort.Int())),
+				External: types.Int64Null(),
+				IP:       types.StringValue(binding.HostIP),
+				Protocol: types.StringValue(natPort.Proto()),
+			}
+			if binding.HostIP == "" {
+				port.IP = types.StringValue("0.0.0.0")
+			}
+			if hostPort, err := strconv.ParseInt(binding.HostPort, 10, 64); err == nil && hostPort > 0 {
+				port.External = types.Int64Value(hostPort)
+			}
+			ports = append(ports, port)
+		}
+	}
+	if len(ports) > 0 || len(data.Ports) > 0 {
+		data.Ports = orderLike(ports, data.Ports, portKey)
+	}
+
+	// Volume mounts. Anonymous volumes declared by the image are skipped.
+	var volumes []VolumeModel
+	for _, m := range containerJSON.Mounts {
+		vol := VolumeModel{
+			VolumeName:    types.StringNull(),
+			HostPath:      types.StringNull(),
+			ContainerPath: types.StringValue(m.Destination),
+			ReadOnly:      types.BoolValue(!m.RW),
+		}
+
+		switch m.Type {
+		case mount.TypeVolume:
+			if _, declared := defaults.Volumes[m.Destination]; declared && !slices.ContainsFunc(data.Volumes, func(v VolumeModel) bool {
+				return v.ContainerPath.ValueString() == m.Destination
+			}) {
+				continue
+			}
+			vol.VolumeName = types.StringValue(m.Name)
+		case mount.TypeBind:
+			vol.HostPath = types.StringValue(m.Source)
+		default:
+			continue
+		}
+
+		volumes = append(volumes, vol)
+	}
+	if len(volumes) > 0 || len(data.Volumes) > 0 {
+		data.Volumes = orderLike(volumes, data.Volumes, func(v VolumeModel) string {
+			return v.ContainerPath.ValueString()
+		})
+	}
+
+	// Healthcheck
+	data.Healthcheck = mapHealthcheck(ctx, config.Healthcheck, defaults.Healthcheck, data.Healthcheck, diagnostics)
+}
+
+// mapHealthcheck converts a container healthcheck into its model, keeping the duration strings from state
+// when they are equivalent
+func mapHealthcheck(ctx context.Context, healthcheck, inherited *container.HealthConfig, prior *HealthcheckModel, diagnostics *diag.Diagnostics) *HealthcheckModel {
+	if healthcheck == nil || len(healthcheck.Test) == 0 {
+		return nil
+	}
+	if prior == nil && inherited != nil && slices.Equal(healthcheck.Test, inherited.Test) &&
+		healthcheck.Interval == inherited.Interval && healthcheck.Timeout == inherited.Timeout &&
+		healthcheck.StartPeriod == inherited.StartPeriod && healthcheck.Retries == inherited.Retries {
+		return nil
+	}
+
+	test, diags := types.ListValueFrom(ctx, types.StringType, healthcheck.Test)
+	diagnostics.Append(diags...)
+
+	model := &HealthcheckModel{
+		Test:    test,
+		Retries: types.Int64Value(int64(healthcheck.Retries)),
+	}
+	if prior == nil {
+		prior = &HealthcheckModel{}
+	}
+	model.Interval = formatDuration(healthcheck.Interval, prior.Interval)
+	model.Timeout = formatDuration(healthcheck.Timeout, prior.Timeout)
+	model.StartPeriod = formatDuration(healthcheck.StartPeriod, prior.StartPeriod)
+
+	return model
+}
+
+// formatDuration returns the prior value if it denotes the same duration, so "1m" is not rewritten as "1m0s"
+func formatDuration(d time.Duration, prior types.String) types.String {
+	if !prior.IsNull() && !prior.IsUnknown() {
+		if priorDuration, err := parseDuration(prior.ValueString()); err == nil && priorDuration == int64(d) {
+			return prior
+		}
+	}
+	return types.StringValue(d.String())
+}
+
+// formatRestartPolicy is the inverse of parseRestartPolicy
+func formatRestartPolicy(policy container.RestartPolicy) string {
+	switch {
+	case policy.Name == "":
+		return string(container.RestartPolicyDisabled)
+	case policy.IsOnFailure() && policy.MaximumRetryCount > 0:
+		return fmt.Sprintf("%s:%d", policy.Name, policy.MaximumRetryCount)
+	default:
+		return string(policy.Name)
+	}
+}
+
+func portKey(p PortModel) string {
+	return fmt.Sprintf("%d/%s/%s/%d", p.Internal.ValueInt64(), p.Protocol.ValueString(), p.IP.ValueString(), p.External.ValueInt64())
+}
+
+// orderLike sorts items so that those already present in prior keep their position, followed by new items
+// sorted by key. This keeps list blocks stable against the unordered data returned by the daemon.
+func orderLike[T any](items, prior []T, key func(T) string) []T {
+	position := make(map[string]int, len(prior))
+	for i, p := range prior {
+		position[key(p)] = i
+	}
+
+	rank := func(item T) int {
+		if i, ok := position[key(item)]; ok {
+			return i
+		}
+		return len(prior)
+	}
+
+	slices.SortStableFunc(items, func(a, b T) int {
+		if c := rank(a) - rank(b); c != 0 {
+			return c
+		}
+		return strings.Compare(key(a), key(b))
+	})
+	return items
+}
+
+// withoutInherited returns the entries of values that are not inherited unchanged, keeping tracked keys
+func withoutInherited(values, inherited, tracked map[string]string) map[string]string {
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		if inheritedValue, ok := inherited[k]; ok && inheritedValue == v {
+			if _, ok := tracked[k]; !ok {
+				continue
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// inheritedString returns null when the value is empty or is the inherited default and was not tracked in state
+func inheritedString(value, inherited string, prior types.String) types.String {
+	if value == "" || (prior.IsNull() && value == inherited) {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// inheritedStringList returns null when the list is empty or is the inherited default and was not tracked in state
+func inheritedStringList(ctx context.Context, values, inherited []string, prior types.List, diagnostics *diag.Diagnostics) types.List {
+	if prior.IsNull() && slices.Equal(values, inherited) {
+		return types.ListNull(types.StringType)
+	}
+	return stringListOrNull(ctx, values, diagnostics)
+}
+
+func stringListOrNull(ctx context.Context, values []string, diagnostics *diag.Diagnostics) types.List {
+	if len(values) == 0 {
+		return types.ListNull(types.StringType)
+	}
+	list, diags := types.ListValueFrom(ctx, types.StringType, values)
+	diagnostics.Append(diags...)
+	return list
+}
+
+func stringMapOrNull(ctx context.Context, values map[string]string, diagnostics *diag.Diagnostics) types.Map {
+	if len(values) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	m, diags := types.MapValueFrom(ctx, types.StringType, values)
+	diagnostics.Append(diags...)
+	return m
+}
+
+// envToMap converts KEY=value pairs into a map
+func envToMap(env []string) map[string]string {
+	result := make(map[string]string, len(env))
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		result[k] = v
+	}
+	return result
+}
+
+// parseRestartPolicy converts a restart string such as "on-failure:3" into a Docker restart policy
+func parseRestartPolicy(restart string) container.RestartPolicy {
+	policy := container.RestartPolicy{Name: container.RestartPolicyMode(restart)}
+	if strings.HasPrefix(restart, "on-failure") {
+		parts := strings.Split(restart, ":")
+		if len(parts) == 2 {
+			var maxRetries int
+			fmt.Sscanf(parts[1], "%d", &maxRetries)
+			policy.Name = container.RestartPolicyOnFailure
+			policy.MaximumRetryCount = maxRetries
+		}
 	}
+	return policy
 }
 
 func parseDuration(s string) (int64, error) {
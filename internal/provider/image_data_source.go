@@ -14,7 +14,7 @@ import (
 var _ datasource.DataSource = &ImageDataSource{}
 
 type ImageDataSource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type ImageDataSourceModel struct {
@@ -77,7 +77,7 @@ func (d *ImageDataSource) Configure(ctx context.Context, req datasource.Configur
 		return
 	}
 
-	d.client = providerData.DockerClient
+	d.clients = providerData.DockerClients
 }
 
 func (d *ImageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -88,9 +88,14 @@ func (d *ImageDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
+	client := engineClient(d.clients, docker.DefaultEndpoint, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	imageName := data.Name.ValueString()
 
-	imageInspect, _, err := d.client.ImageInspectWithRaw(ctx, imageName)
+	imageInspect, _, err := client.ImageInspectWithRaw(ctx, imageName)
 	if err != nil {
 		if strings.Contains(err.Error(), "No such image") {
 			resp.Diagnostics.AddError("Image Not Found", fmt.Sprintf("Image %s not found", imageName))
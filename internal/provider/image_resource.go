@@ -2,15 +2,13 @@ package provider
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"io"
 	"strings"
 
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/registry"
 	"github.com/elioseverojunior/terraform-provider-docker/internal/docker"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -24,10 +22,12 @@ import (
 var (
 	_ resource.Resource                = &ImageResource{}
 	_ resource.ResourceWithImportState = &ImageResource{}
+	_ resource.ResourceWithModifyPlan  = &ImageResource{}
 )
 
 type ImageResource struct {
-	client *docker.Client
+	clients    *docker.ClientPool
+	registries *docker.AuthResolver
 }
 
 type ImageResourceModel struct {
@@ -41,6 +41,12 @@ type ImageResourceModel struct {
 
 	// Registry authentication
 	RegistryAuth *RegistryAuthModel `tfsdk:"registry_auth"`
+
+	// Local build
+	Build *ImageBuildModel `tfsdk:"build"`
+
+	// Engine endpoint from the provider hosts
+	DockerHost types.String `tfsdk:"docker_host"`
 }
 
 type RegistryAuthModel struct {
@@ -49,6 +55,17 @@ type RegistryAuthModel struct {
 	Password types.String `tfsdk:"password"`
 }
 
+type ImageBuildModel struct {
+	Context     types.String `tfsdk:"context"`
+	Dockerfile  types.String `tfsdk:"dockerfile"`
+	Target      types.String `tfsdk:"target"`
+	BuildArgs   types.Map    `tfsdk:"build_args"`
+	Labels      types.Map    `tfsdk:"labels"`
+	NoCache     types.Bool   `tfsdk:"no_cache"`
+	Platform    types.String `tfsdk:"platform"`
+	ContextHash types.String `tfsdk:"context_hash"`
+}
+
 func NewImageResource() resource.Resource {
 	return &ImageResource{}
 }
@@ -59,7 +76,7 @@ func (r *ImageResource) Metadata(ctx context.Context, req resource.MetadataReque
 
 func (r *ImageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages Docker images. Pulls images from a registry and optionally keeps them locally.",
+		Description: "Manages Docker images. Pulls images from a registry, or builds them locally from a Dockerfile, and optionally keeps them locally.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "The ID of this resource.",
@@ -68,6 +85,13 @@ func (r *ImageResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"docker_host": schema.StringAttribute{
+				Description: "Name of the provider hosts endpoint to manage this resource on. Defaults to the provider's own host. Changing this forces a new resource. To import onto a named host, prefix the import ID with '<host>/'.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "The name of the Docker image, including any tags or SHA256 repo digests (e.g., nginx:latest, nginx@sha256:...).",
 				Required:    true,
@@ -88,12 +112,12 @@ func (r *ImageResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Default:     booldefault.StaticBool(false),
 			},
 			"pull_triggers": schema.ListAttribute{
-				Description: "List of values which cause an image pull when changed.",
+				Description: "List of values which cause an image pull when changed. Ignored when build is set.",
 				Optional:    true,
 				ElementType: types.StringType,
 			},
 			"image_id": schema.StringAttribute{
-				Description: "The ID of the pulled image.",
+				Description: "The ID of the pulled or built image.",
 				Computed:    true,
 			},
 			"repo_digest": schema.StringAttribute{
@@ -120,6 +144,45 @@ func (r *ImageResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					},
 				},
 			},
+			"build": schema.SingleNestedBlock{
+				Description: "Build the image locally instead of pulling it. The built image is tagged with name. The image is rebuilt only when the build context contents, the Dockerfile or the build settings change.",
+				Attributes: map[string]schema.Attribute{
+					"context": schema.StringAttribute{
+						Description: "Path to the build context directory. Files matching patterns in its .dockerignore are excluded.",
+						Required:    true,
+					},
+					"dockerfile": schema.StringAttribute{
+						Description: "Dockerfile path relative to context, which must be inside the context. Default is 'Dockerfile'.",
+						Optional:    true,
+					},
+					"target": schema.StringAttribute{
+						Description: "Target build stage.",
+						Optional:    true,
+					},
+					"build_args": schema.MapAttribute{
+						Description: "Build arguments.",
+						Optional:    true,
+						ElementType: types.StringType,
+					},
+					"labels": schema.MapAttribute{
+						Description: "Image labels.",
+						Optional:    true,
+						ElementType: types.StringType,
+					},
+					"no_cache": schema.BoolAttribute{
+						Description: "Do not use cache when building.",
+						Optional:    true,
+					},
+					"platform": schema.StringAttribute{
+						Description: "Target platform (e.g., 'linux/amd64').",
+						Optional:    true,
+					},
+					"context_hash": schema.StringAttribute{
+						Description: "SHA256 digest of the build context contents, excluding files matched by .dockerignore.",
+						Computed:    true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -138,7 +201,8 @@ func (r *ImageResource) Configure(ctx context.Context, req resource.ConfigureReq
 		return
 	}
 
-	r.client = providerData.DockerClient
+	r.clients = providerData.DockerClients
+	r.registries = providerData.Registries
 }
 
 func (r *ImageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -149,61 +213,37 @@ func (r *ImageResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	imageName := data.Name.ValueString()
-	tflog.Debug(ctx, "Pulling Docker image", map[string]interface{}{
-		"name": imageName,
-	})
-
-	pullOptions := image.PullOptions{}
-
-	// Configure registry authentication if provided
-	if data.RegistryAuth != nil && !data.RegistryAuth.Username.IsNull() {
-		authConfig := registry.AuthConfig{
-			Username: data.RegistryAuth.Username.ValueString(),
-			Password: data.RegistryAuth.Password.ValueString(),
-		}
-		if !data.RegistryAuth.Address.IsNull() {
-			authConfig.ServerAddress = data.RegistryAuth.Address.ValueString()
-		}
-
-		encodedJSON, err := json.Marshal(authConfig)
-		if err != nil {
-			resp.Diagnostics.AddError("Registry Auth Error", fmt.Sprintf("Failed to encode registry auth: %s", err))
-			return
-		}
-		pullOptions.RegistryAuth = base64.URLEncoding.EncodeToString(encodedJSON)
-	}
-
-	// Pull the image
-	reader, err := r.client.ImagePull(ctx, imageName, pullOptions)
-	if err != nil {
-		resp.Diagnostics.AddError("Image Pull Error", fmt.Sprintf("Unable to pull image %s: %s", imageName, err))
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	defer reader.Close()
 
-	// Consume the output to complete the pull
-	_, err = io.Copy(io.Discard, reader)
-	if err != nil {
-		resp.Diagnostics.AddError("Image Pull Error", fmt.Sprintf("Error during image pull %s: %s", imageName, err))
+	imageName := data.Name.ValueString()
+
+	var repoDigest string
+	if data.Build != nil {
+		r.buildImage(ctx, client, &data, &resp.Diagnostics)
+	} else {
+		repoDigest = r.pullImage(ctx, client, &data, &resp.Diagnostics)
+	}
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Inspect the pulled image to get its ID and digest
-	imageInspect, _, err := r.client.ImageInspectWithRaw(ctx, imageName)
+	// Inspect the image to get its ID and digest
+	imageInspect, _, err := client.ImageInspectWithRaw(ctx, imageName)
 	if err != nil {
-		resp.Diagnostics.AddError("Image Inspect Error", fmt.Sprintf("Unable to inspect pulled image %s: %s", imageName, err))
+		resp.Diagnostics.AddError("Image Inspect Error", fmt.Sprintf("Unable to inspect image %s: %s", imageName, err))
 		return
 	}
 
 	data.ID = types.StringValue(imageInspect.ID)
 	data.ImageID = types.StringValue(imageInspect.ID)
 
-	// Get repo digest if available
-	if len(imageInspect.RepoDigests) > 0 {
-		data.RepoDigest = types.StringValue(imageInspect.RepoDigests[0])
+	if repoDigest != "" {
+		data.RepoDigest = types.StringValue(repoDigest)
 	} else {
-		data.RepoDigest = types.StringNull()
+		data.RepoDigest = imageRepoDigest("", imageInspect.RepoDigests)
 	}
 
 	tflog.Debug(ctx, "Created Docker image resource", map[string]interface{}{
@@ -222,9 +262,14 @@ func (r *ImageResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	imageName := data.Name.ValueString()
 
-	imageInspect, _, err := r.client.ImageInspectWithRaw(ctx, imageName)
+	imageInspect, _, err := client.ImageInspectWithRaw(ctx, imageName)
 	if err != nil {
 		if strings.Contains(err.Error(), "No such image") {
 			resp.State.RemoveResource(ctx)
@@ -235,12 +280,7 @@ func (r *ImageResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	}
 
 	data.ImageID = types.StringValue(imageInspect.ID)
-
-	if len(imageInspect.RepoDigests) > 0 {
-		data.RepoDigest = types.StringValue(imageInspect.RepoDigests[0])
-	} else {
-		data.RepoDigest = types.StringNull()
-	}
+	data.RepoDigest = imageRepoDigest(data.RepoDigest.ValueString(), imageInspect.RepoDigests)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -253,54 +293,45 @@ func (r *ImageResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	// Re-pull the image if pull_triggers changed
-	imageName := data.Name.ValueString()
-
-	pullOptions := image.PullOptions{}
-
-	if data.RegistryAuth != nil && !data.RegistryAuth.Username.IsNull() {
-		authConfig := registry.AuthConfig{
-			Username: data.RegistryAuth.Username.ValueString(),
-			Password: data.RegistryAuth.Password.ValueString(),
-		}
-		if !data.RegistryAuth.Address.IsNull() {
-			authConfig.ServerAddress = data.RegistryAuth.Address.ValueString()
-		}
-
-		encodedJSON, err := json.Marshal(authConfig)
-		if err != nil {
-			resp.Diagnostics.AddError("Registry Auth Error", fmt.Sprintf("Failed to encode registry auth: %s", err))
-			return
-		}
-		pullOptions.RegistryAuth = base64.URLEncoding.EncodeToString(encodedJSON)
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	reader, err := r.client.ImagePull(ctx, imageName, pullOptions)
-	if err != nil {
-		resp.Diagnostics.AddError("Image Pull Error", fmt.Sprintf("Unable to pull image %s: %s", imageName, err))
+	var state ImageResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	defer reader.Close()
 
-	_, err = io.Copy(io.Discard, reader)
-	if err != nil {
-		resp.Diagnostics.AddError("Image Pull Error", fmt.Sprintf("Error during image pull %s: %s", imageName, err))
+	imageName := data.Name.ValueString()
+
+	var repoDigest string
+	if data.Build != nil {
+		// Rebuild only if the build context or build settings changed
+		if state.Build == nil || !data.Build.equal(state.Build) {
+			r.buildImage(ctx, client, &data, &resp.Diagnostics)
+		}
+	} else {
+		// Re-pull the image if pull_triggers changed
+		repoDigest = r.pullImage(ctx, client, &data, &resp.Diagnostics)
+	}
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	imageInspect, _, err := r.client.ImageInspectWithRaw(ctx, imageName)
+	imageInspect, _, err := client.ImageInspectWithRaw(ctx, imageName)
 	if err != nil {
-		resp.Diagnostics.AddError("Image Inspect Error", fmt.Sprintf("Unable to inspect pulled image %s: %s", imageName, err))
+		resp.Diagnostics.AddError("Image Inspect Error", fmt.Sprintf("Unable to inspect image %s: %s", imageName, err))
 		return
 	}
 
 	data.ID = types.StringValue(imageInspect.ID)
 	data.ImageID = types.StringValue(imageInspect.ID)
-
-	if len(imageInspect.RepoDigests) > 0 {
-		data.RepoDigest = types.StringValue(imageInspect.RepoDigests[0])
+	if repoDigest != "" {
+		data.RepoDigest = types.StringValue(repoDigest)
 	} else {
-		data.RepoDigest = types.StringNull()
+		data.RepoDigest = imageRepoDigest("", imageInspect.RepoDigests)
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -314,6 +345,11 @@ func (r *ImageResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if data.KeepLocally.ValueBool() {
 		tflog.Debug(ctx, "Keeping image locally as configured", map[string]interface{}{
 			"name": data.Name.ValueString(),
@@ -328,7 +364,7 @@ func (r *ImageResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		PruneChildren: true,
 	}
 
-	_, err := r.client.ImageRemove(ctx, imageName, removeOptions)
+	_, err := client.ImageRemove(ctx, imageName, removeOptions)
 	if err != nil {
 		if strings.Contains(err.Error(), "No such image") {
 			return
@@ -343,5 +379,173 @@ func (r *ImageResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (r *ImageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	_, id := importHostID(ctx, r.clients, req.ID, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), id)...)
+}
+
+// ModifyPlan computes the build context hash so that changes to files in the context trigger a rebuild
+func (r *ImageResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var build *ImageBuildModel
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("build"), &build)...)
+	if resp.Diagnostics.HasError() || build == nil {
+		return
+	}
+
+	if build.Context.IsUnknown() || build.Dockerfile.IsUnknown() {
+		return
+	}
+
+	contextHash, err := docker.HashBuildContext(build.Context.ValueString(), build.Dockerfile.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("build").AtName("context"),
+			"Build Context Error",
+			fmt.Sprintf("Unable to hash build context %s: %s", build.Context.ValueString(), err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("build").AtName("context_hash"), contextHash)...)
+}
+
+// imageRepoDigest returns the repo digest to record for an image. The one already in state is kept while the
+// image still carries it, so that images with several repo digests don't flip between them.
+func imageRepoDigest(known string, repoDigests []string) types.String {
+	for _, repoDigest := range repoDigests {
+		if repoDigest == known {
+			return types.StringValue(known)
+		}
+	}
+	if len(repoDigests) > 0 {
+		return types.StringValue(repoDigests[0])
+	}
+	return types.StringNull()
+}
+
+// registryAuths returns the credentials of the registry_auth block. Without an address they apply to the
+// registry of the image itself.
+func (r *ImageResource) registryAuths(data *ImageResourceModel) []registry.AuthConfig {
+	if data.RegistryAuth == nil || data.RegistryAuth.Username.IsNull() {
+		return nil
+	}
+
+	address := data.RegistryAuth.Address.ValueString()
+	if address == "" {
+		address, _ = docker.ImageRegistry(data.Name.ValueString())
+	}
+
+	return []registry.AuthConfig{{
+		ServerAddress: address,
+		Username:      data.RegistryAuth.Username.ValueString(),
+		Password:      data.RegistryAuth.Password.ValueString(),
+	}}
+}
+
+// pullImage pulls the image and returns its repo digest as the daemon reported it, empty when it reported none
+func (r *ImageResource) pullImage(ctx context.Context, client *docker.Client, data *ImageResourceModel, diagnostics *diag.Diagnostics) string {
+	imageName := data.Name.ValueString()
+	tflog.Debug(ctx, "Pulling Docker image", map[string]interface{}{
+		"name": imageName,
+	})
+
+	pullOptions := image.PullOptions{
+		RegistryAuth: imageAuth(r.registries, imageName, r.registryAuths(data), diagnostics),
+	}
+	if diagnostics.HasError() {
+		return ""
+	}
+
+	reader, err := client.ImagePull(ctx, imageName, pullOptions)
+	if err != nil {
+		diagnostics.AddError("Image Pull Error", fmt.Sprintf("Unable to pull image %s: %s", imageName, err))
+		return ""
+	}
+	defer reader.Close()
+
+	result, err := docker.ReadProgress(ctx, reader)
+	if err != nil {
+		diagnostics.AddError("Image Pull Error", fmt.Sprintf("Error during image pull %s: %s", imageName, err))
+		return ""
+	}
+	if result.Digest == "" {
+		return ""
+	}
+
+	repoDigest, err := docker.RepoDigest(imageName, result.Digest)
+	if err != nil {
+		diagnostics.AddError("Image Pull Error", err.Error())
+		return ""
+	}
+	return repoDigest
+}
+
+func (r *ImageResource) buildImage(ctx context.Context, client *docker.Client, data *ImageResourceModel, diagnostics *diag.Diagnostics) {
+	imageName := data.Name.ValueString()
+	b := data.Build
+
+	buildOptions := docker.BuildOptions{
+		ContextDir: b.Context.ValueString(),
+		Dockerfile: b.Dockerfile.ValueString(),
+		Tags:       []string{imageName},
+		Target:     b.Target.ValueString(),
+		NoCache:    b.NoCache.ValueBool(),
+		Platform:   b.Platform.ValueString(),
+	}
+
+	if !b.BuildArgs.IsNull() {
+		buildArgs := make(map[string]string)
+		diagnostics.Append(b.BuildArgs.ElementsAs(ctx, &buildArgs, false)...)
+		buildOptions.BuildArgs = make(map[string]*string, len(buildArgs))
+		for k, v := range buildArgs {
+			buildOptions.BuildArgs[k] = &v
+		}
+	}
+
+	if !b.Labels.IsNull() {
+		labels := make(map[string]string)
+		diagnostics.Append(b.Labels.ElementsAs(ctx, &labels, false)...)
+		buildOptions.Labels = labels
+	}
+
+	// Make registry credentials available for pulling base images
+	buildOptions.AuthConfigs = buildAuthConfigs(ctx, r.registries, r.registryAuths(data), diagnostics)
+
+	if diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Building Docker image", map[string]interface{}{
+		"name":    imageName,
+		"context": buildOptions.ContextDir,
+	})
+
+	if _, err := client.BuildImage(ctx, buildOptions); err != nil {
+		diagnostics.AddError("Image Build Error", fmt.Sprintf("Unable to build image %s from context %s: %s", imageName, buildOptions.ContextDir, err))
+		return
+	}
+
+	// The hash is normally known from the plan; compute it if it was not
+	if b.ContextHash.IsUnknown() || b.ContextHash.IsNull() {
+		contextHash, err := docker.HashBuildContext(buildOptions.ContextDir, buildOptions.Dockerfile)
+		if err != nil {
+			diagnostics.AddError("Image Build Error", fmt.Sprintf("Unable to hash build context %s: %s", buildOptions.ContextDir, err))
+			return
+		}
+		b.ContextHash = types.StringValue(contextHash)
+	}
+}
+
+func (m *ImageBuildModel) equal(other *ImageBuildModel) bool {
+	return m.Context.Equal(other.Context) &&
+		m.Dockerfile.Equal(other.Dockerfile) &&
+		m.Target.Equal(other.Target) &&
+		m.BuildArgs.Equal(other.BuildArgs) &&
+		m.Labels.Equal(other.Labels) &&
+		m.NoCache.Equal(other.NoCache) &&
+		m.Platform.Equal(other.Platform) &&
+		m.ContextHash.Equal(other.ContextHash)
 }
@@ -17,7 +17,7 @@ import (
 var _ datasource.DataSource = &LogsDataSource{}
 
 type LogsDataSource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type LogsDataSourceModel struct {
@@ -108,7 +108,7 @@ func (d *LogsDataSource) Configure(ctx context.Context, req datasource.Configure
 		return
 	}
 
-	d.client = providerData.DockerClient
+	d.clients = providerData.DockerClients
 }
 
 func (d *LogsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -119,6 +119,11 @@ func (d *LogsDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
+	client := engineClient(d.clients, docker.DefaultEndpoint, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	containerName := data.Name.ValueString()
 
 	// Set defaults
@@ -158,7 +163,7 @@ func (d *LogsDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		options.Until = data.Until.ValueString()
 	}
 
-	logs, err := d.client.ContainerLogs(ctx, containerName, options)
+	logs, err := client.ContainerLogs(ctx, containerName, options)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to Read Container Logs",
@@ -170,34 +175,10 @@ func (d *LogsDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 
 	var buf bytes.Buffer
 	if discardHeaders {
-		// Docker multiplexes stdout/stderr with 8-byte headers
-		// We need to strip these headers
-		header := make([]byte, 8)
-		for {
-			_, err := io.ReadFull(logs, header)
-			if err != nil {
-				if err == io.EOF {
-					break
-				}
-				// If it's not a full 8 bytes, just copy the rest
-				buf.Write(header[:])
-				io.Copy(&buf, logs)
-				break
-			}
-
-			// Header format: [STREAM_TYPE, 0, 0, 0, SIZE1, SIZE2, SIZE3, SIZE4]
-			size := int(header[4])<<24 | int(header[5])<<16 | int(header[6])<<8 | int(header[7])
-			if size > 0 {
-				line := make([]byte, size)
-				_, err := io.ReadFull(logs, line)
-				if err != nil {
-					break
-				}
-				buf.Write(line)
-			}
-		}
+		// Docker multiplexes stdout/stderr with 8-byte headers, which need to be stripped
+		_ = docker.DemuxLogs(logs, &buf, &buf)
 	} else {
-		io.Copy(&buf, logs)
+		_, _ = io.Copy(&buf, logs)
 	}
 
 	data.ID = types.StringValue(fmt.Sprintf("%s-%d", containerName, time.Now().Unix()))
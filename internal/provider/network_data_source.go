@@ -15,7 +15,7 @@ import (
 var _ datasource.DataSource = &NetworkDataSource{}
 
 type NetworkDataSource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type NetworkDataSourceModel struct {
@@ -88,7 +88,7 @@ func (d *NetworkDataSource) Configure(ctx context.Context, req datasource.Config
 		return
 	}
 
-	d.client = providerData.DockerClient
+	d.clients = providerData.DockerClients
 }
 
 func (d *NetworkDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -99,9 +99,14 @@ func (d *NetworkDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
+	client := engineClient(d.clients, docker.DefaultEndpoint, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	networkName := data.Name.ValueString()
 
-	networkInspect, err := d.client.NetworkInspect(ctx, networkName, network.InspectOptions{})
+	networkInspect, err := client.NetworkInspect(ctx, networkName, network.InspectOptions{})
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "No such network") {
 			resp.Diagnostics.AddError("Network Not Found", fmt.Sprintf("Network %s not found", networkName))
@@ -24,7 +24,7 @@ var (
 )
 
 type NetworkResource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type NetworkResourceModel struct {
@@ -39,6 +39,7 @@ type NetworkResourceModel struct {
 	Scope      types.String      `tfsdk:"scope"`
 	IPAM       *NetworkIPAMModel `tfsdk:"ipam"`
 	IPv6       types.Bool        `tfsdk:"ipv6"`
+	DockerHost types.String      `tfsdk:"docker_host"`
 }
 
 type NetworkIPAMModel struct {
@@ -73,6 +74,13 @@ func (r *NetworkResource) Schema(ctx context.Context, req resource.SchemaRequest
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"docker_host": schema.StringAttribute{
+				Description: "Name of the provider hosts endpoint to manage this resource on. Defaults to the provider's own host. Changing this forces a new resource. To import onto a named host, prefix the import ID with '<host>/'.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "The name of the Docker network.",
 				Required:    true,
@@ -189,7 +197,7 @@ func (r *NetworkResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	r.client = providerData.DockerClient
+	r.clients = providerData.DockerClients
 }
 
 func (r *NetworkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -200,6 +208,11 @@ func (r *NetworkResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	networkName := data.Name.ValueString()
 	tflog.Debug(ctx, "Creating Docker network", map[string]interface{}{
 		"name": networkName,
@@ -272,7 +285,7 @@ func (r *NetworkResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	// Create the network
-	networkResp, err := r.client.NetworkCreate(ctx, networkName, createOptions)
+	networkResp, err := client.NetworkCreate(ctx, networkName, createOptions)
 	if err != nil {
 		resp.Diagnostics.AddError("Network Create Error", fmt.Sprintf("Unable to create network %s: %s", networkName, err))
 		return
@@ -281,7 +294,7 @@ func (r *NetworkResource) Create(ctx context.Context, req resource.CreateRequest
 	data.ID = types.StringValue(networkResp.ID)
 
 	// Inspect to get computed attributes
-	networkInspect, err := r.client.NetworkInspect(ctx, networkResp.ID, network.InspectOptions{})
+	networkInspect, err := client.NetworkInspect(ctx, networkResp.ID, network.InspectOptions{})
 	if err != nil {
 		resp.Diagnostics.AddError("Network Inspect Error", fmt.Sprintf("Unable to inspect network %s: %s", networkName, err))
 		return
@@ -305,9 +318,14 @@ func (r *NetworkResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	networkID := data.ID.ValueString()
 
-	networkInspect, err := r.client.NetworkInspect(ctx, networkID, network.InspectOptions{})
+	networkInspect, err := client.NetworkInspect(ctx, networkID, network.InspectOptions{})
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "No such network") {
 			resp.State.RemoveResource(ctx)
@@ -368,13 +386,18 @@ func (r *NetworkResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	networkID := data.ID.ValueString()
 
 	tflog.Debug(ctx, "Deleting Docker network", map[string]interface{}{
 		"id": networkID,
 	})
 
-	err := r.client.NetworkRemove(ctx, networkID)
+	err := client.NetworkRemove(ctx, networkID)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "No such network") {
 			return
@@ -389,5 +412,6 @@ func (r *NetworkResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (r *NetworkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	_, id := importHostID(ctx, r.clients, req.ID, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
@@ -14,7 +14,7 @@ import (
 var _ datasource.DataSource = &NetworksDataSource{}
 
 type NetworksDataSource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type NetworksDataSourceModel struct {
@@ -104,7 +104,7 @@ func (d *NetworksDataSource) Configure(ctx context.Context, req datasource.Confi
 		return
 	}
 
-	d.client = providerData.DockerClient
+	d.clients = providerData.DockerClients
 }
 
 func (d *NetworksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -115,8 +115,13 @@ func (d *NetworksDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
+	client := engineClient(d.clients, docker.DefaultEndpoint, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// List all networks
-	networks, err := d.client.NetworkList(ctx, network.ListOptions{})
+	networks, err := client.NetworkList(ctx, network.ListOptions{})
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to List Networks", fmt.Sprintf("Unable to list Docker networks: %s", err))
 		return
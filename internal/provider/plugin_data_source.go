@@ -13,7 +13,7 @@ import (
 var _ datasource.DataSource = &PluginDataSource{}
 
 type PluginDataSource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type PluginDataSourceModel struct {
@@ -75,7 +75,7 @@ func (d *PluginDataSource) Configure(ctx context.Context, req datasource.Configu
 		return
 	}
 
-	d.client = providerData.DockerClient
+	d.clients = providerData.DockerClients
 }
 
 func (d *PluginDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -86,9 +86,14 @@ func (d *PluginDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
+	client := engineClient(d.clients, docker.DefaultEndpoint, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	pluginName := data.Name.ValueString()
 
-	plugin, _, err := d.client.PluginInspectWithRaw(ctx, pluginName)
+	plugin, _, err := client.PluginInspectWithRaw(ctx, pluginName)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to Read Plugin",
@@ -2,11 +2,17 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/docker/docker/api/types/registry"
+	"github.com/elioseverojunior/terraform-provider-docker/internal/distribution"
 	"github.com/elioseverojunior/terraform-provider-docker/internal/docker"
 	"github.com/elioseverojunior/terraform-provider-docker/internal/dockerhub"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -23,19 +29,58 @@ type DockerProvider struct {
 
 // ProviderData holds both Docker Engine and Hub clients
 type ProviderData struct {
-	DockerClient *docker.Client
-	HubClient    *dockerhub.Client
+	// DockerClients holds the default endpoint and the named endpoints from the hosts attribute.
+	// Engine clients connect on first use, so Hub-only configurations don't need a reachable daemon.
+	DockerClients *docker.ClientPool
+	// Registries resolves registry credentials from registry_auth and the docker config file
+	Registries *docker.AuthResolver
+	// RegistryClient talks to registries directly over the Distribution API
+	RegistryClient *distribution.Client
+	HubClient      *dockerhub.Client
+}
+
+// DockerHostModel is a named Docker Engine endpoint in the provider hosts attribute
+type DockerHostModel struct {
+	Host              types.String `tfsdk:"host"`
+	TLSVerify         types.Bool   `tfsdk:"tls_verify"`
+	CertPath          types.String `tfsdk:"cert_path"`
+	CACert            types.String `tfsdk:"ca_cert"`
+	Cert              types.String `tfsdk:"cert"`
+	Key               types.String `tfsdk:"key"`
+	SSHOpts           types.List   `tfsdk:"ssh_opts"`
+	SSHPrivateKey     types.String `tfsdk:"ssh_private_key"`
+	SSHKnownHostsFile types.String `tfsdk:"ssh_known_hosts_file"`
+}
+
+// ProviderRegistryAuthModel is a registry_auth block of the provider
+type ProviderRegistryAuthModel struct {
+	Address  types.String `tfsdk:"address"`
+	Username types.String `tfsdk:"username"`
+	Password types.String `tfsdk:"password"`
 }
 
 type DockerProviderModel struct {
 	// Docker Engine configuration
 	Host      types.String `tfsdk:"host"`
+	Context   types.String `tfsdk:"context"`
 	TLSVerify types.Bool   `tfsdk:"tls_verify"`
 	CertPath  types.String `tfsdk:"cert_path"`
 	CACert    types.String `tfsdk:"ca_cert"`
 	Cert      types.String `tfsdk:"cert"`
 	Key       types.String `tfsdk:"key"`
 
+	// SSH transport configuration for ssh:// hosts
+	SSHOpts           types.List   `tfsdk:"ssh_opts"`
+	SSHPrivateKey     types.String `tfsdk:"ssh_private_key"`
+	SSHKnownHostsFile types.String `tfsdk:"ssh_known_hosts_file"`
+
+	// Additional named Docker Engine endpoints
+	Hosts types.Map `tfsdk:"hosts"`
+
+	// Registry credentials
+	RegistryAuth []ProviderRegistryAuthModel `tfsdk:"registry_auth"`
+	ConfigFile   types.String                `tfsdk:"config_file"`
+
 	// Docker Hub configuration
 	HubUsername types.String `tfsdk:"hub_username"`
 	HubPassword types.String `tfsdk:"hub_password"`
@@ -61,7 +106,11 @@ func (p *DockerProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 		Attributes: map[string]schema.Attribute{
 			// Docker Engine attributes
 			"host": schema.StringAttribute{
-				Description: "The Docker daemon socket to connect to. Defaults to unix:///var/run/docker.sock. Can also be set via DOCKER_HOST environment variable.",
+				Description: "The Docker daemon socket to connect to, such as unix:///var/run/docker.sock, tcp://host:2376 or ssh://user@host. Defaults to unix:///var/run/docker.sock. Can also be set via DOCKER_HOST environment variable.",
+				Optional:    true,
+			},
+			"context": schema.StringAttribute{
+				Description: "The docker CLI context to connect with, read from the context store in ~/.docker/contexts. Can also be set via DOCKER_CONTEXT environment variable. Defaults to the current context, unless DOCKER_HOST is set. Conflicts with host.",
 				Optional:    true,
 			},
 			"tls_verify": schema.BoolAttribute{
@@ -87,6 +136,74 @@ func (p *DockerProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"ssh_opts": schema.ListAttribute{
+				Description: "SSH options for ssh:// hosts, in ssh command line form such as [\"-o\", \"Port=2222\"], \"-i ~/.ssh/deploy\" or \"StrictHostKeyChecking=no\". Supported options are Port, User, IdentityFile, UserKnownHostsFile, StrictHostKeyChecking and ConnectTimeout.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"ssh_private_key": schema.StringAttribute{
+				Description: "PEM-encoded private key content for ssh:// hosts. Without it, identity files from ssh_opts, the SSH agent (SSH_AUTH_SOCK) and the default keys in ~/.ssh are used.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"ssh_known_hosts_file": schema.StringAttribute{
+				Description: "Path to the known_hosts file used to verify ssh:// hosts. Defaults to ~/.ssh/known_hosts.",
+				Optional:    true,
+			},
+			"hosts": schema.MapNestedAttribute{
+				Description: "Additional named Docker Engine endpoints. Resources select one with their docker_host attribute. Clients are only connected when a resource uses them.",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"host": schema.StringAttribute{
+							Description: "The Docker daemon socket to connect to, such as tcp://host:2376 or ssh://user@host.",
+							Required:    true,
+						},
+						"tls_verify": schema.BoolAttribute{
+							Description: "Enable TLS verification for the host.",
+							Optional:    true,
+						},
+						"cert_path": schema.StringAttribute{
+							Description: "Path to directory containing TLS certificates (ca.pem, cert.pem, key.pem).",
+							Optional:    true,
+						},
+						"ca_cert": schema.StringAttribute{
+							Description: "PEM-encoded CA certificate content for TLS verification.",
+							Optional:    true,
+							Sensitive:   true,
+						},
+						"cert": schema.StringAttribute{
+							Description: "PEM-encoded client certificate content for TLS authentication.",
+							Optional:    true,
+							Sensitive:   true,
+						},
+						"key": schema.StringAttribute{
+							Description: "PEM-encoded client key content for TLS authentication.",
+							Optional:    true,
+							Sensitive:   true,
+						},
+						"ssh_opts": schema.ListAttribute{
+							Description: "SSH options for ssh:// hosts, in the same form as the provider ssh_opts.",
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+						"ssh_private_key": schema.StringAttribute{
+							Description: "PEM-encoded private key content for ssh:// hosts.",
+							Optional:    true,
+							Sensitive:   true,
+						},
+						"ssh_known_hosts_file": schema.StringAttribute{
+							Description: "Path to the known_hosts file used to verify ssh:// hosts. Defaults to ~/.ssh/known_hosts.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+
+			"config_file": schema.StringAttribute{
+				Description: "Path to the docker CLI config file whose auths, credsStore and credHelpers supply registry credentials. Defaults to config.json in DOCKER_CONFIG or ~/.docker.",
+				Optional:    true,
+			},
 
 			// Docker Hub attributes
 			"hub_username": schema.StringAttribute{
@@ -104,6 +221,28 @@ func (p *DockerProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 				Sensitive:   true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"registry_auth": schema.ListNestedBlock{
+				Description: "Registry credentials used by every image pull, build, push and service. They take precedence over the docker config file, and resource level credentials take precedence over them.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"address": schema.StringAttribute{
+							Description: "The address of the registry (e.g., docker.io, ghcr.io, registry.example.com:5000).",
+							Required:    true,
+						},
+						"username": schema.StringAttribute{
+							Description: "The username for registry authentication.",
+							Required:    true,
+						},
+						"password": schema.StringAttribute{
+							Description: "The password or access token for registry authentication.",
+							Required:    true,
+							Sensitive:   true,
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -115,14 +254,20 @@ func (p *DockerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	if !config.Host.IsNull() && !config.Context.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("context"),
+			"Conflicting Docker Endpoint",
+			"Only one of host and context can be set.",
+		)
+		return
+	}
+
 	// Docker Engine configuration
 	host := os.Getenv("DOCKER_HOST")
 	if !config.Host.IsNull() {
 		host = config.Host.ValueString()
 	}
-	if host == "" {
-		host = "unix:///var/run/docker.sock"
-	}
 
 	tlsVerify := os.Getenv("DOCKER_TLS_VERIFY") == "1"
 	if !config.TLSVerify.IsNull() {
@@ -134,6 +279,47 @@ func (p *DockerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		certPath = config.CertPath.ValueString()
 	}
 
+	// Resolve the docker CLI context the same way as the docker CLI: an explicit context takes precedence
+	// over DOCKER_HOST, which takes precedence over DOCKER_CONTEXT and the current context in config.json
+	contextName := config.Context.ValueString()
+	if contextName == "" && host == "" {
+		var err error
+		contextName, err = docker.CurrentContextName()
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Resolve Docker Context", err.Error())
+			return
+		}
+	}
+
+	var tlsSkipVerify bool
+	if contextName != "" && contextName != docker.DefaultContextName {
+		dockerContext, err := docker.LoadContext(contextName)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("context"),
+				"Unable to Load Docker Context",
+				err.Error(),
+			)
+			return
+		}
+
+		tflog.Debug(ctx, "Using Docker context", map[string]interface{}{
+			"context": contextName,
+			"host":    dockerContext.Host,
+		})
+
+		host = dockerContext.Host
+		if config.TLSVerify.IsNull() && config.CertPath.IsNull() {
+			tlsVerify = dockerContext.TLSPath != "" || dockerContext.SkipTLSVerify
+			certPath = dockerContext.TLSPath
+			tlsSkipVerify = dockerContext.SkipTLSVerify
+		}
+	}
+
+	if host == "" {
+		host = "unix:///var/run/docker.sock"
+	}
+
 	var caCert, cert, key string
 	if !config.CACert.IsNull() {
 		caCert = config.CACert.ValueString()
@@ -146,7 +332,7 @@ func (p *DockerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	}
 
 	// Validate TLS configuration
-	if tlsVerify {
+	if tlsVerify && !tlsSkipVerify {
 		hasCertPath := certPath != ""
 		hasCertContent := caCert != "" && cert != "" && key != ""
 
@@ -160,26 +346,57 @@ func (p *DockerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		}
 	}
 
+	var sshOpts []string
+	resp.Diagnostics.Append(config.SSHOpts.ElementsAs(ctx, &sshOpts, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Create Docker Engine client
 	clientConfig := docker.ClientConfig{
-		Host:      host,
-		TLSVerify: tlsVerify,
-		CertPath:  certPath,
-		CACert:    caCert,
-		Cert:      cert,
-		Key:       key,
+		Host:          host,
+		TLSVerify:     tlsVerify,
+		TLSSkipVerify: tlsSkipVerify,
+		CertPath:      certPath,
+		CACert:        caCert,
+		Cert:          cert,
+		Key:           key,
+		SSH: docker.SSHConfig{
+			Opts:           sshOpts,
+			PrivateKey:     config.SSHPrivateKey.ValueString(),
+			KnownHostsFile: config.SSHKnownHostsFile.ValueString(),
+		},
 	}
 
-	dockerClient, err := docker.NewClient(clientConfig)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Docker Client",
-			"An unexpected error occurred when creating the Docker client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Docker Client Error: "+err.Error(),
-		)
+	dockerClients := docker.NewClientPool()
+	dockerClients.Register(docker.DefaultEndpoint, clientConfig)
+
+	var hosts map[string]DockerHostModel
+	resp.Diagnostics.Append(config.Hosts.ElementsAs(ctx, &hosts, false)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	for name, hostConfig := range hosts {
+		dockerClients.Register(name, hostClientConfig(ctx, name, hostConfig, &resp.Diagnostics))
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Registry credentials
+	configFile := docker.DefaultConfigFile()
+	if !config.ConfigFile.IsNull() {
+		configFile = config.ConfigFile.ValueString()
+	}
+
+	registryAuths := make([]registry.AuthConfig, 0, len(config.RegistryAuth))
+	for _, auth := range config.RegistryAuth {
+		registryAuths = append(registryAuths, registry.AuthConfig{
+			ServerAddress: auth.Address.ValueString(),
+			Username:      auth.Username.ValueString(),
+			Password:      auth.Password.ValueString(),
+		})
+	}
 
 	// Docker Hub configuration
 	hubUsername := os.Getenv("DOCKER_HUB_USERNAME")
@@ -206,6 +423,7 @@ func (p *DockerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 			Token:    hubToken,
 		}
 
+		var err error
 		hubClient, err = dockerhub.NewClient(ctx, hubConfig)
 		if err != nil {
 			// Don't fail provider initialization, just warn
@@ -222,16 +440,128 @@ func (p *DockerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		}
 	}
 
-	// Create provider data containing both clients
+	// Create provider data containing all clients
+	registries := docker.NewAuthResolver(configFile, registryAuths)
 	providerData := &ProviderData{
-		DockerClient: dockerClient,
-		HubClient:    hubClient,
+		DockerClients:  dockerClients,
+		Registries:     registries,
+		RegistryClient: distribution.NewClient(registries),
+		HubClient:      hubClient,
 	}
 
 	resp.DataSourceData = providerData
 	resp.ResourceData = providerData
 }
 
+// hostClientConfig converts a named endpoint from the hosts attribute into a client configuration
+func hostClientConfig(ctx context.Context, name string, hostConfig DockerHostModel, diagnostics *diag.Diagnostics) docker.ClientConfig {
+	clientConfig := docker.ClientConfig{
+		Host:      hostConfig.Host.ValueString(),
+		TLSVerify: hostConfig.TLSVerify.ValueBool(),
+		CertPath:  hostConfig.CertPath.ValueString(),
+		CACert:    hostConfig.CACert.ValueString(),
+		Cert:      hostConfig.Cert.ValueString(),
+		Key:       hostConfig.Key.ValueString(),
+		SSH: docker.SSHConfig{
+			PrivateKey:     hostConfig.SSHPrivateKey.ValueString(),
+			KnownHostsFile: hostConfig.SSHKnownHostsFile.ValueString(),
+		},
+	}
+
+	diagnostics.Append(hostConfig.SSHOpts.ElementsAs(ctx, &clientConfig.SSH.Opts, false)...)
+
+	if clientConfig.TLSVerify && clientConfig.CertPath == "" && (clientConfig.CACert == "" || clientConfig.Cert == "" || clientConfig.Key == "") {
+		diagnostics.AddAttributeError(
+			path.Root("hosts").AtMapKey(name).AtName("tls_verify"),
+			"Missing TLS Configuration",
+			"When tls_verify is enabled, you must provide either cert_path or all of ca_cert, cert, and key.",
+		)
+	}
+
+	return clientConfig
+}
+
+// engineClient returns the Engine client for a docker_host endpoint, connecting on first use. The provider's
+// own host is used when the endpoint is empty.
+func engineClient(clients *docker.ClientPool, dockerHost string, diagnostics *diag.Diagnostics) *docker.Client {
+	if clients == nil {
+		diagnostics.AddError(
+			"Unconfigured Docker Client",
+			"The provider has not been configured. Please report this issue to the provider developers.",
+		)
+		return nil
+	}
+
+	client, err := clients.Get(dockerHost)
+	if err != nil {
+		if dockerHost != docker.DefaultEndpoint {
+			diagnostics.AddAttributeError(
+				path.Root("docker_host"),
+				"Unable to Connect to Docker Host",
+				err.Error(),
+			)
+			return nil
+		}
+		diagnostics.AddError(
+			"Unable to Create Docker Client",
+			"An unexpected error occurred when creating the Docker client. "+
+				"If the error is not clear, please contact the provider developers.\n\n"+
+				"Docker Client Error: "+err.Error(),
+		)
+		return nil
+	}
+	return client
+}
+
+// imageAuth returns the X-Registry-Auth value for pulling or pushing an image. Credentials from the
+// resource's own auth block take precedence over the provider registry_auth and the docker config file,
+// but only for the registry their address names; a warning says when none of them applies to the image.
+func imageAuth(registries *docker.AuthResolver, imageName string, resourceAuths []registry.AuthConfig, diagnostics *diag.Diagnostics) string {
+	encodedAuth, err := registries.With(resourceAuths).EncodedImageAuth(imageName)
+	if err != nil {
+		diagnostics.AddError("Registry Auth Error", fmt.Sprintf("Failed to resolve registry credentials for %s: %s", imageName, err))
+		return ""
+	}
+
+	if len(resourceAuths) > 0 {
+		address, _ := docker.ImageRegistry(imageName)
+		matched := slices.ContainsFunc(resourceAuths, func(auth registry.AuthConfig) bool {
+			return docker.RegistryHostname(auth.ServerAddress) == docker.RegistryHostname(address)
+		})
+		if !matched {
+			diagnostics.AddWarning(
+				"Registry Auth Not Used",
+				fmt.Sprintf("None of the resource's registry credentials have an address matching registry %s of image %s, so they are "+
+					"not used and credentials come from the provider registry_auth and the docker config file instead. Set the address to %s to use them.",
+					address, imageName, address),
+			)
+		}
+	}
+	return encodedAuth
+}
+
+// importHostID splits an import ID of the form <host>/<id>, where host names a provider hosts entry, and sets
+// docker_host from it. Other IDs, including image names with a registry, are imported on the provider's own host.
+func importHostID(ctx context.Context, clients *docker.ClientPool, importID string, resp *resource.ImportStateResponse) (string, string) {
+	host, id, ok := strings.Cut(importID, "/")
+	if !ok || host == docker.DefaultEndpoint || clients == nil || !clients.Has(host) {
+		return docker.DefaultEndpoint, importID
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("docker_host"), host)...)
+	return host, id
+}
+
+// buildAuthConfigs returns the credentials of every known registry so that builds can pull private base images
+func buildAuthConfigs(ctx context.Context, registries *docker.AuthResolver, resourceAuths []registry.AuthConfig, diagnostics *diag.Diagnostics) map[string]registry.AuthConfig {
+	authConfigs, err := registries.With(resourceAuths).AuthConfigs(ctx)
+	if err != nil {
+		diagnostics.AddError("Registry Auth Error", fmt.Sprintf("Failed to resolve registry credentials: %s", err))
+		return nil
+	}
+	return authConfigs
+}
+
 func (p *DockerProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		// Docker Engine resources
@@ -249,6 +579,7 @@ func (p *DockerProvider) Resources(ctx context.Context) []func() resource.Resour
 		// Registry resources
 		NewTagResource,
 		NewRegistryImageResource,
+		NewRegistryImageCopyResource,
 
 		// Docker Hub resources (require hub credentials)
 		NewHubRepositoryResource,
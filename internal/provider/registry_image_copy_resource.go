@@ -0,0 +1,372 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/elioseverojunior/terraform-provider-docker/internal/distribution"
+	"github.com/elioseverojunior/terraform-provider-docker/internal/docker"
+	"github.com/elioseverojunior/terraform-provider-docker/internal/dockerhub"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	tftypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var (
+	_ resource.Resource                = &RegistryImageCopyResource{}
+	_ resource.ResourceWithImportState = &RegistryImageCopyResource{}
+)
+
+type RegistryImageCopyResource struct {
+	registries     *docker.AuthResolver
+	registryClient *distribution.Client
+	hubClient      *dockerhub.Client
+}
+
+type RegistryImageCopyResourceModel struct {
+	ID                 tftypes.String `tfsdk:"id"`
+	SourceImage        tftypes.String `tfsdk:"source_image"`
+	TargetImage        tftypes.String `tfsdk:"target_image"`
+	KeepRemotely       tftypes.Bool   `tfsdk:"keep_remotely"`
+	InsecureSkipVerify tftypes.Bool   `tfsdk:"insecure_skip_verify"`
+	Triggers           tftypes.Map    `tfsdk:"triggers"`
+	Sha256Digest       tftypes.String `tfsdk:"sha256_digest"`
+}
+
+func NewRegistryImageCopyResource() resource.Resource {
+	return &RegistryImageCopyResource{}
+}
+
+func (r *RegistryImageCopyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_image_copy"
+}
+
+func (r *RegistryImageCopyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Copies an image, including every platform of a multi-platform image, from one registry repository to another over the Distribution API. " +
+			"No Docker daemon is used and layers are not downloaded to the machine running Terraform. Within one registry, layers are mounted from the source repository instead of transferred. " +
+			"Credentials come from the provider registry_auth and the docker config file.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of this resource (same as target_image).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"source_image": schema.StringAttribute{
+				Description: "The image to copy, with tag or digest (e.g., 'staging.example.com/app:v1.2.0').",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					// An imported copy has no source_image yet, and setting it should not copy the image again
+					stringplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+						resp.RequiresReplace = !req.StateValue.IsNull()
+					}, "Changing the source image copies the image again.", "Changing the source image copies the image again."),
+				},
+			},
+			"target_image": schema.StringAttribute{
+				Description: "The image to create, with tag (e.g., 'prod.example.com/app:v1.2.0').",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"keep_remotely": schema.BoolAttribute{
+				Description: "If true, the target image will not be deleted from the registry on destroy. Default is false. Deleting removes the manifest by digest, together with every other tag that points at it; a copy within the source repository is left in place while the source still points at the same digest.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "If true, skip TLS certificate verification when talking to the source and target registries.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"triggers": schema.MapAttribute{
+				Description: "A map of arbitrary values that, when changed, will cause the image to be copied again. Use it to follow a moving source tag.",
+				Optional:    true,
+				ElementType: tftypes.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"sha256_digest": schema.StringAttribute{
+				Description: "The digest of the copied manifest or image index. It is the same in both registries.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *RegistryImageCopyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.registries = providerData.Registries
+	r.registryClient = providerData.RegistryClient
+	r.hubClient = providerData.HubClient
+}
+
+// client returns the registry client, skipping TLS verification when the resource asks for it
+func (r *RegistryImageCopyResource) client(data *RegistryImageCopyResourceModel) *distribution.Client {
+	if data.InsecureSkipVerify.ValueBool() {
+		return r.registryClient.WithInsecureSkipVerify()
+	}
+	return r.registryClient
+}
+
+func (r *RegistryImageCopyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RegistryImageCopyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.registryClient == nil {
+		resp.Diagnostics.AddError(
+			"Unconfigured Registry Client",
+			"The provider has not been configured. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
+	sourceImage := data.SourceImage.ValueString()
+	targetImage := data.TargetImage.ValueString()
+
+	src, err := distribution.ParseReference(sourceImage)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("source_image"), "Invalid Image Name", err.Error())
+		return
+	}
+	dst, err := distribution.ParseReference(targetImage)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("target_image"), "Invalid Image Name", err.Error())
+		return
+	}
+	if dst.Digest != "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("target_image"),
+			"Invalid Image Name",
+			fmt.Sprintf("The target image %s must be named by tag, not by digest.", targetImage),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Copying image between registries", map[string]interface{}{
+		"source": sourceImage,
+		"target": targetImage,
+	})
+
+	desc, err := r.client(&data).Copy(ctx, src, dst, func(blob ocispec.Descriptor, mounted bool) {
+		tflog.Trace(ctx, "Copied image content", map[string]interface{}{
+			"digest":     blob.Digest.String(),
+			"media_type": blob.MediaType,
+			"size":       blob.Size,
+			"mounted":    mounted,
+		})
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Registry Image Copy Failed",
+			fmt.Sprintf("Failed to copy image %s to %s: %s", sourceImage, targetImage, err),
+		)
+		return
+	}
+
+	data.ID = tftypes.StringValue(targetImage)
+	data.Sha256Digest = tftypes.StringValue(desc.Digest.String())
+
+	tflog.Debug(ctx, "Copied image between registries", map[string]interface{}{
+		"target": targetImage,
+		"digest": desc.Digest.String(),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RegistryImageCopyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data RegistryImageCopyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.registryClient == nil {
+		resp.Diagnostics.AddError(
+			"Unconfigured Registry Client",
+			"The provider has not been configured. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
+	targetImage := data.TargetImage.ValueString()
+	dst, err := distribution.ParseReference(targetImage)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Image Name", err.Error())
+		return
+	}
+
+	desc, err := r.client(&data).HeadManifest(ctx, dst)
+	if err != nil {
+		if distribution.IsStatus(err, http.StatusNotFound) {
+			tflog.Debug(ctx, "Target image not found in registry, removing from state", map[string]interface{}{
+				"target": targetImage,
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Registry Image Read Failed",
+			fmt.Sprintf("Failed to read image %s from the registry: %s", targetImage, err),
+		)
+		return
+	}
+
+	// A target tag that was pushed over outside of Terraform no longer holds the copy, so copy it again
+	if !data.Sha256Digest.IsNull() && data.Sha256Digest.ValueString() != desc.Digest.String() {
+		tflog.Debug(ctx, "Target image digest changed, removing from state", map[string]interface{}{
+			"target":   targetImage,
+			"expected": data.Sha256Digest.ValueString(),
+			"actual":   desc.Digest.String(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.Sha256Digest = tftypes.StringValue(desc.Digest.String())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RegistryImageCopyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only keep_remotely and insecure_skip_verify can change in place; everything else requires replacement
+	var data RegistryImageCopyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RegistryImageCopyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data RegistryImageCopyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	targetImage := data.TargetImage.ValueString()
+
+	if data.KeepRemotely.ValueBool() {
+		tflog.Debug(ctx, "Keeping image in registry (keep_remotely=true)", map[string]interface{}{
+			"target": targetImage,
+		})
+		return
+	}
+
+	if r.registryClient == nil {
+		resp.Diagnostics.AddError(
+			"Unconfigured Registry Client",
+			"The provider has not been configured. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
+	if r.sharesSourceManifest(ctx, &data, &resp.Diagnostics) {
+		return
+	}
+
+	deleteRegistryImage(ctx, r.client(&data), r.hubClient, r.registries, targetImage, &resp.Diagnostics)
+}
+
+// sharesSourceManifest reports whether deleting the target would also delete the source image. Outside Docker
+// Hub a manifest can only be deleted by digest, which removes every tag of the repository pointing at it, so a
+// copy within one repository (app:staging to app:prod) is left in place with a warning while the source still
+// references the copied digest.
+func (r *RegistryImageCopyResource) sharesSourceManifest(ctx context.Context, data *RegistryImageCopyResourceModel, diagnostics *diag.Diagnostics) bool {
+	if data.SourceImage.IsNull() {
+		return false
+	}
+
+	src, err := distribution.ParseReference(data.SourceImage.ValueString())
+	if err != nil {
+		return false
+	}
+	dst, err := distribution.ParseReference(data.TargetImage.ValueString())
+	if err != nil {
+		return false
+	}
+	if dst.Registry == "docker.io" || src.Registry != dst.Registry || src.Repository != dst.Repository {
+		return false
+	}
+
+	// The source may be pinned by digest, otherwise ask the registry what its tag points at now
+	contentDigest := src.Digest
+	if contentDigest == "" {
+		desc, err := r.client(data).HeadManifest(ctx, src)
+		switch {
+		case err == nil:
+			contentDigest = desc.Digest.String()
+		case distribution.IsStatus(err, http.StatusNotFound):
+			return false
+		default:
+			diagnostics.AddError(
+				"Registry Image Deletion Failed",
+				fmt.Sprintf("Unable to check whether source image %s still references %s: %s", src, data.Sha256Digest.ValueString(), err),
+			)
+			return true
+		}
+	}
+
+	if contentDigest != data.Sha256Digest.ValueString() {
+		return false
+	}
+
+	diagnostics.AddWarning(
+		"Registry Image Shared With Source",
+		fmt.Sprintf("Image %s has the same digest as source image %s in the same repository. Manifests can only be deleted by digest, "+
+			"which would delete the source image too, so %s remains in the registry. Set keep_remotely=true to suppress this warning.",
+			data.TargetImage.ValueString(), data.SourceImage.ValueString(), data.TargetImage.ValueString()),
+	)
+	return true
+}
+
+func (r *RegistryImageCopyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import by target image name; Read fills in the digest
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("target_image"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("keep_remotely"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("insecure_skip_verify"), false)...)
+	// Note: source_image cannot be determined from import - user must update config
+	resp.Diagnostics.AddWarning(
+		"Source Image Required",
+		"The source_image attribute must be provided in the Terraform configuration after import.",
+	)
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elioseverojunior/terraform-provider-docker/internal/distribution"
+	"github.com/elioseverojunior/terraform-provider-docker/internal/docker"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tftypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSharesSourceManifest(t *testing.T) {
+	const (
+		sharedDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		otherDigest  = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+
+	// The registry knows app:staging, which points at sharedDigest, and app:moved, which points elsewhere
+	heads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		heads++
+		switch req.URL.Path {
+		case "/v2/app/manifests/staging":
+			w.Header().Set("Docker-Content-Digest", sharedDigest)
+		case "/v2/app/manifests/moved":
+			w.Header().Set("Docker-Content-Digest", otherDigest)
+		case "/v2/app/manifests/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+		w.Header().Set("Content-Length", "100")
+	}))
+	defer server.Close()
+	registry := strings.TrimPrefix(server.URL, "http://")
+
+	tests := []struct {
+		name        string
+		source      string
+		target      string
+		wantShared  bool
+		wantHead    bool
+		wantWarning bool
+		wantError   bool
+	}{
+		{"source tag still at target digest", registry + "/app:staging", registry + "/app:prod", true, true, true, false},
+		{"source tag moved", registry + "/app:moved", registry + "/app:prod", false, true, false, false},
+		{"source tag deleted", registry + "/app:gone", registry + "/app:prod", false, true, false, false},
+		{"source pinned to target digest", registry + "/app@" + sharedDigest, registry + "/app:prod", true, false, true, false},
+		{"other repository", registry + "/staging/app:v1", registry + "/app:prod", false, false, false, false},
+		{"docker hub", "library/app:staging", "library/app:prod", false, false, false, false},
+		{"registry error", registry + "/app:broken", registry + "/app:prod", true, true, false, true},
+	}
+
+	r := &RegistryImageCopyResource{registryClient: distribution.NewClient(docker.NewAuthResolver("", nil))}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			heads = 0
+			data := RegistryImageCopyResourceModel{
+				SourceImage:  tftypes.StringValue(tt.source),
+				TargetImage:  tftypes.StringValue(tt.target),
+				Sha256Digest: tftypes.StringValue(sharedDigest),
+			}
+
+			var diagnostics diag.Diagnostics
+			shared := r.sharesSourceManifest(context.Background(), &data, &diagnostics)
+
+			if shared != tt.wantShared {
+				t.Errorf("sharesSourceManifest = %t, want %t", shared, tt.wantShared)
+			}
+			if (heads > 0) != tt.wantHead {
+				t.Errorf("sent %d requests to the registry, want a request: %t", heads, tt.wantHead)
+			}
+			if (diagnostics.WarningsCount() > 0) != tt.wantWarning {
+				t.Errorf("warnings = %v, want a warning: %t", diagnostics.Warnings(), tt.wantWarning)
+			}
+			if diagnostics.HasError() != tt.wantError {
+				t.Errorf("errors = %v, want an error: %t", diagnostics.Errors(), tt.wantError)
+			}
+		})
+	}
+}
@@ -4,22 +4,33 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/elioseverojunior/terraform-provider-docker/internal/docker"
+	"github.com/elioseverojunior/terraform-provider-docker/internal/distribution"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ datasource.DataSource = &RegistryImageDataSource{}
 
 type RegistryImageDataSource struct {
-	client *docker.Client
+	registryClient *distribution.Client
 }
 
 type RegistryImageDataSourceModel struct {
-	ID           types.String `tfsdk:"id"`
-	Name         types.String `tfsdk:"name"`
-	SHA256Digest types.String `tfsdk:"sha256_digest"`
+	ID           types.String                 `tfsdk:"id"`
+	Name         types.String                 `tfsdk:"name"`
+	SHA256Digest types.String                 `tfsdk:"sha256_digest"`
+	MediaType    types.String                 `tfsdk:"media_type"`
+	Size         types.Int64                  `tfsdk:"size"`
+	Manifests    []RegistryImageManifestModel `tfsdk:"manifests"`
+}
+
+type RegistryImageManifestModel struct {
+	Platform  types.String `tfsdk:"platform"`
+	Digest    types.String `tfsdk:"digest"`
+	MediaType types.String `tfsdk:"media_type"`
+	Size      types.Int64  `tfsdk:"size"`
 }
 
 func NewRegistryImageDataSource() datasource.DataSource {
@@ -32,7 +43,7 @@ func (d *RegistryImageDataSource) Metadata(ctx context.Context, req datasource.M
 
 func (d *RegistryImageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Reads the digest of a Docker image from a registry.",
+		Description: "Reads the digest of a Docker image from a registry. The registry is queried directly over the Distribution API, so the image does not need to exist locally and no Docker daemon is used. Credentials come from the provider registry_auth and the docker config file.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "The ID of this data source.",
@@ -43,9 +54,41 @@ func (d *RegistryImageDataSource) Schema(ctx context.Context, req datasource.Sch
 				Required:    true,
 			},
 			"sha256_digest": schema.StringAttribute{
-				Description: "The sha256 digest of the image.",
+				Description: "The digest of the manifest or image index the name points at in the registry.",
 				Computed:    true,
 			},
+			"media_type": schema.StringAttribute{
+				Description: "The media type of the manifest, such as application/vnd.oci.image.index.v1+json for multi-platform images.",
+				Computed:    true,
+			},
+			"size": schema.Int64Attribute{
+				Description: "The size of the manifest in bytes.",
+				Computed:    true,
+			},
+			"manifests": schema.ListNestedAttribute{
+				Description: "The per-platform manifests of an image index. Empty for single-platform images.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"platform": schema.StringAttribute{
+							Description: "The platform of the manifest, such as linux/arm64/v8.",
+							Computed:    true,
+						},
+						"digest": schema.StringAttribute{
+							Description: "The digest of the manifest.",
+							Computed:    true,
+						},
+						"media_type": schema.StringAttribute{
+							Description: "The media type of the manifest.",
+							Computed:    true,
+						},
+						"size": schema.Int64Attribute{
+							Description: "The size of the manifest in bytes.",
+							Computed:    true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -64,7 +107,7 @@ func (d *RegistryImageDataSource) Configure(ctx context.Context, req datasource.
 		return
 	}
 
-	d.client = providerData.DockerClient
+	d.registryClient = providerData.RegistryClient
 }
 
 func (d *RegistryImageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -75,27 +118,69 @@ func (d *RegistryImageDataSource) Read(ctx context.Context, req datasource.ReadR
 		return
 	}
 
+	if d.registryClient == nil {
+		resp.Diagnostics.AddError(
+			"Unconfigured Registry Client",
+			"The provider has not been configured. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
 	imageName := data.Name.ValueString()
+	ref, err := distribution.ParseReference(imageName)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Image Name", err.Error())
+		return
+	}
 
-	// Inspect the local image to get its digest
-	inspect, _, err := d.client.ImageInspectWithRaw(ctx, imageName)
+	tflog.Debug(ctx, "Reading image manifest from registry", map[string]interface{}{
+		"name":     imageName,
+		"registry": ref.Registry,
+	})
+
+	desc, err := d.registryClient.HeadManifest(ctx, ref)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Failed to Inspect Image",
-			fmt.Sprintf("Unable to inspect image %s: %s. Make sure the image exists locally.", imageName, err),
+			"Failed to Read Registry Image",
+			fmt.Sprintf("Unable to read image %s from registry %s: %s", imageName, ref.Registry, err),
 		)
 		return
 	}
 
-	data.ID = types.StringValue(inspect.ID)
-
-	// Get digest from RepoDigests if available
-	if len(inspect.RepoDigests) > 0 {
-		// RepoDigests are in format "repo@sha256:..."
-		data.SHA256Digest = types.StringValue(inspect.RepoDigests[0])
-	} else {
-		// Fall back to image ID
-		data.SHA256Digest = types.StringValue(inspect.ID)
+	data.ID = types.StringValue(desc.Digest.String())
+	data.SHA256Digest = types.StringValue(desc.Digest.String())
+	data.MediaType = types.StringValue(desc.MediaType)
+	data.Size = types.Int64Value(desc.Size)
+	data.Manifests = []RegistryImageManifestModel{}
+
+	if distribution.IsIndex(desc.MediaType) {
+		// Pin the index by digest so that the platforms listed match the digest reported
+		ref.Digest = desc.Digest.String()
+		_, index, err := d.registryClient.GetIndex(ctx, ref)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to Read Registry Image",
+				fmt.Sprintf("Unable to read image index %s from registry %s: %s", imageName, ref.Registry, err),
+			)
+			return
+		}
+
+		for _, manifest := range index.Manifests {
+			platform := ""
+			if manifest.Platform != nil {
+				platform = manifest.Platform.OS + "/" + manifest.Platform.Architecture
+				if manifest.Platform.Variant != "" {
+					platform += "/" + manifest.Platform.Variant
+				}
+			}
+
+			data.Manifests = append(data.Manifests, RegistryImageManifestModel{
+				Platform:  types.StringValue(platform),
+				Digest:    types.StringValue(manifest.Digest.String()),
+				MediaType: types.StringValue(manifest.MediaType),
+				Size:      types.Int64Value(manifest.Size),
+			})
+		}
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -2,15 +2,17 @@ package provider
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"io"
+	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/registry"
+	"github.com/elioseverojunior/terraform-provider-docker/internal/distribution"
 	"github.com/elioseverojunior/terraform-provider-docker/internal/docker"
+	"github.com/elioseverojunior/terraform-provider-docker/internal/dockerhub"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -19,6 +21,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	tftypes "github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 var (
@@ -27,7 +30,10 @@ var (
 )
 
 type RegistryImageResource struct {
-	client *docker.Client
+	clients        *docker.ClientPool
+	registries     *docker.AuthResolver
+	registryClient *distribution.Client
+	hubClient      *dockerhub.Client
 }
 
 type RegistryImageResourceModel struct {
@@ -39,6 +45,7 @@ type RegistryImageResourceModel struct {
 	Sha256Digest       tftypes.String `tfsdk:"sha256_digest"`
 	AuthConfig         tftypes.List   `tfsdk:"auth_config"`
 	Build              tftypes.List   `tfsdk:"build"`
+	DockerHost         tftypes.String `tfsdk:"docker_host"`
 }
 
 type RegistryAuthConfigModel struct {
@@ -57,6 +64,7 @@ type RegistryBuildModel struct {
 	NoCache     tftypes.Bool   `tfsdk:"no_cache"`
 	ForceRemove tftypes.Bool   `tfsdk:"force_remove"`
 	Platform    tftypes.String `tfsdk:"platform"`
+	Platforms   tftypes.List   `tfsdk:"platforms"`
 }
 
 func NewRegistryImageResource() resource.Resource {
@@ -69,7 +77,8 @@ func (r *RegistryImageResource) Metadata(ctx context.Context, req resource.Metad
 
 func (r *RegistryImageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages the lifecycle of a Docker image in a registry. Pushes images to registries and can optionally build them first.",
+		Description: "Manages the lifecycle of a Docker image in a registry. Pushes images to registries and can optionally build them first." +
+			" A multi-platform build also leaves one public tag per platform in the repository, named after the image tag with the platform appended (e.g., 'v1.0-linux-arm64'), because the Docker daemon can only push images by tag; the image index references these platform images and they are deleted with it.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "The ID of this resource.",
@@ -78,6 +87,13 @@ func (r *RegistryImageResource) Schema(ctx context.Context, req resource.SchemaR
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"docker_host": schema.StringAttribute{
+				Description: "Name of the provider hosts endpoint to manage this resource on. Defaults to the provider's own host. Changing this forces a new resource.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "The full name of the Docker image including registry and tag (e.g., 'registry.example.com/myimage:v1.0').",
 				Required:    true,
@@ -86,13 +102,13 @@ func (r *RegistryImageResource) Schema(ctx context.Context, req resource.SchemaR
 				},
 			},
 			"keep_remotely": schema.BoolAttribute{
-				Description: "If true, the image will not be deleted from the registry on destroy. Default is false.",
+				Description: "If true, the image will not be deleted from the registry on destroy. Default is false. Deleting removes the manifest by digest, together with every other tag that points at it. Docker Hub tags are deleted through the Hub API with the provider hub credentials.",
 				Optional:    true,
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
 			},
 			"insecure_skip_verify": schema.BoolAttribute{
-				Description: "If true, skip TLS certificate verification when pushing to the registry.",
+				Description: "If true, skip TLS certificate verification when the provider talks to the registry directly, such as when reading the image digest, pushing a multi-platform image index or deleting the image on destroy.",
 				Optional:    true,
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
@@ -109,11 +125,11 @@ func (r *RegistryImageResource) Schema(ctx context.Context, req resource.SchemaR
 		},
 		Blocks: map[string]schema.Block{
 			"auth_config": schema.ListNestedBlock{
-				Description: "Registry authentication configuration.",
+				Description: "Registry authentication configuration. Each block only applies to the registry its address names, unlike earlier versions that used the first block for any registry; a warning is shown when no block matches the registry of the image.",
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
 						"address": schema.StringAttribute{
-							Description: "Registry server address.",
+							Description: "Registry server address. The credentials are only used for images on the registry with this hostname.",
 							Required:    true,
 						},
 						"username": schema.StringAttribute{
@@ -133,11 +149,11 @@ func (r *RegistryImageResource) Schema(ctx context.Context, req resource.SchemaR
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
 						"context": schema.StringAttribute{
-							Description: "Build context path.",
+							Description: "Path to the build context directory. Files matching patterns in its .dockerignore are excluded.",
 							Required:    true,
 						},
 						"dockerfile": schema.StringAttribute{
-							Description: "Dockerfile path relative to context. Default is 'Dockerfile'.",
+							Description: "Dockerfile path relative to context, which must be inside the context. Default is 'Dockerfile'.",
 							Optional:    true,
 						},
 						"target": schema.StringAttribute{
@@ -172,9 +188,14 @@ func (r *RegistryImageResource) Schema(ctx context.Context, req resource.SchemaR
 							Default:     booldefault.StaticBool(false),
 						},
 						"platform": schema.StringAttribute{
-							Description: "Target platform (e.g., 'linux/amd64').",
+							Description: "Target platform (e.g., 'linux/amd64'). Conflicts with platforms.",
 							Optional:    true,
 						},
+						"platforms": schema.ListAttribute{
+							Description: "Target platforms (e.g., ['linux/amd64', 'linux/arm64']). One image is built and pushed per platform under the tag suffixed with the platform (e.g., 'v1.0-linux-arm64'), then an OCI image index referencing them is pushed under the tag itself. The platform tags are deleted together with the image. Conflicts with platform.",
+							Optional:    true,
+							ElementType: tftypes.StringType,
+						},
 					},
 				},
 			},
@@ -196,7 +217,10 @@ func (r *RegistryImageResource) Configure(ctx context.Context, req resource.Conf
 		return
 	}
 
-	r.client = providerData.DockerClient
+	r.clients = providerData.DockerClients
+	r.registries = providerData.Registries
+	r.registryClient = providerData.RegistryClient
+	r.hubClient = providerData.HubClient
 }
 
 func (r *RegistryImageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -207,127 +231,17 @@ func (r *RegistryImageResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
-	imageName := data.Name.ValueString()
-
-	// Get auth config
-	encodedAuth := ""
-	if !data.AuthConfig.IsNull() && len(data.AuthConfig.Elements()) > 0 {
-		var authConfigs []RegistryAuthConfigModel
-		resp.Diagnostics.Append(data.AuthConfig.ElementsAs(ctx, &authConfigs, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		if len(authConfigs) > 0 {
-			authConfig := registry.AuthConfig{
-				ServerAddress: authConfigs[0].Address.ValueString(),
-				Username:      authConfigs[0].Username.ValueString(),
-				Password:      authConfigs[0].Password.ValueString(),
-			}
-			encodedJSON, _ := json.Marshal(authConfig)
-			encodedAuth = base64.URLEncoding.EncodeToString(encodedJSON)
-		}
-	}
-
-	// Build if configured
-	if !data.Build.IsNull() && len(data.Build.Elements()) > 0 {
-		tflog.Debug(ctx, "Building Docker image before push", map[string]interface{}{
-			"name": imageName,
-		})
-		// Note: Build functionality would require tar archive creation and build API
-		// For now, we assume the image already exists locally
-		resp.Diagnostics.AddWarning(
-			"Build Not Implemented",
-			"Image building is not yet fully implemented. Please ensure the image exists locally before using this resource.",
-		)
-	}
-
-	tflog.Debug(ctx, "Pushing Docker image to registry", map[string]interface{}{
-		"name": imageName,
-	})
-
-	// Push the image
-	pushReader, err := r.client.ImagePush(ctx, imageName, image.PushOptions{
-		RegistryAuth: encodedAuth,
-	})
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Docker Image Push Failed",
-			fmt.Sprintf("Failed to push image %s: %s", imageName, err),
-		)
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	defer pushReader.Close()
-
-	// Read the push output to completion
-	var digest string
-	decoder := json.NewDecoder(pushReader)
-	for {
-		var pushResponse struct {
-			Status         string `json:"status"`
-			Progress       string `json:"progress"`
-			ProgressDetail struct {
-				Current int64 `json:"current"`
-				Total   int64 `json:"total"`
-			} `json:"progressDetail"`
-			Aux struct {
-				Digest string `json:"Digest"`
-				Size   int64  `json:"Size"`
-				Tag    string `json:"Tag"`
-			} `json:"aux"`
-			Error string `json:"error"`
-		}
 
-		if err := decoder.Decode(&pushResponse); err != nil {
-			if err == io.EOF {
-				break
-			}
-			// Continue on decode errors, not all lines are JSON
-			continue
-		}
-
-		if pushResponse.Error != "" {
-			resp.Diagnostics.AddError(
-				"Docker Image Push Error",
-				fmt.Sprintf("Error during push: %s", pushResponse.Error),
-			)
-			return
-		}
-
-		if pushResponse.Aux.Digest != "" {
-			digest = pushResponse.Aux.Digest
-		}
-	}
-
-	data.ID = tftypes.StringValue(imageName)
-	if digest != "" {
-		data.Sha256Digest = tftypes.StringValue(digest)
-	} else {
-		// Try to get digest from image inspect
-		inspect, _, err := r.client.ImageInspectWithRaw(ctx, imageName)
-		if err == nil && len(inspect.RepoDigests) > 0 {
-			// Extract digest from repo digest (format: registry/image@sha256:xxx)
-			for _, rd := range inspect.RepoDigests {
-				if strings.Contains(rd, "@") {
-					parts := strings.Split(rd, "@")
-					if len(parts) == 2 {
-						digest = parts[1]
-						break
-					}
-				}
-			}
-		}
-		if digest != "" {
-			data.Sha256Digest = tftypes.StringValue(digest)
-		} else {
-			data.Sha256Digest = tftypes.StringNull()
-		}
+	data.ID = tftypes.StringValue(data.Name.ValueString())
+	data.Sha256Digest = r.publish(ctx, client, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	tflog.Debug(ctx, "Pushed Docker image to registry", map[string]interface{}{
-		"name":   imageName,
-		"digest": digest,
-	})
-
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -341,38 +255,38 @@ func (r *RegistryImageResource) Read(ctx context.Context, req resource.ReadReque
 
 	imageName := data.Name.ValueString()
 
-	// Check if image exists locally
-	inspect, _, err := r.client.ImageInspectWithRaw(ctx, imageName)
+	ref, err := distribution.ParseReference(imageName)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Image Name", err.Error())
+		return
+	}
+
+	authConfigs := r.authConfigs(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Ask the registry, so that the digest of a pushed image index is read back as well
+	registryClient := r.client(&data, authConfigs)
+
+	desc, err := registryClient.HeadManifest(ctx, ref)
 	if err != nil {
-		if strings.Contains(err.Error(), "No such image") {
-			// Image doesn't exist locally, but might still exist in registry
-			// We can't easily verify registry existence without credentials
-			tflog.Debug(ctx, "Image not found locally", map[string]interface{}{
+		if distribution.IsStatus(err, http.StatusNotFound) {
+			tflog.Debug(ctx, "Image not found in registry, removing from state", map[string]interface{}{
 				"name": imageName,
 			})
-			// Don't remove from state - the image might still exist in the registry
-			// User should manually verify or re-push
+			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
-			"Docker Image Read Failed",
-			fmt.Sprintf("Failed to read image %s: %s", imageName, err),
+		// Keep the recorded digest when the registry can't be reached, the image might still exist
+		resp.Diagnostics.AddWarning(
+			"Registry Image Read Failed",
+			fmt.Sprintf("Unable to read image %s from the registry, keeping the recorded digest: %s", imageName, err),
 		)
 		return
 	}
 
-	// Update digest if available
-	if len(inspect.RepoDigests) > 0 {
-		for _, rd := range inspect.RepoDigests {
-			if strings.Contains(rd, "@") {
-				parts := strings.Split(rd, "@")
-				if len(parts) == 2 {
-					data.Sha256Digest = tftypes.StringValue(parts[1])
-					break
-				}
-			}
-		}
-	}
+	data.Sha256Digest = tftypes.StringValue(desc.Digest.String())
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -385,6 +299,11 @@ func (r *RegistryImageResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Check if triggers changed - if so, re-push the image
 	var oldData RegistryImageResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &oldData)...)
@@ -392,47 +311,32 @@ func (r *RegistryImageResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
-	triggersChanged := !data.Triggers.Equal(oldData.Triggers)
-	if triggersChanged {
-		// Re-push the image
-		imageName := data.Name.ValueString()
-
-		encodedAuth := ""
-		if !data.AuthConfig.IsNull() && len(data.AuthConfig.Elements()) > 0 {
-			var authConfigs []RegistryAuthConfigModel
-			resp.Diagnostics.Append(data.AuthConfig.ElementsAs(ctx, &authConfigs, false)...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-			if len(authConfigs) > 0 {
-				authConfig := registry.AuthConfig{
-					ServerAddress: authConfigs[0].Address.ValueString(),
-					Username:      authConfigs[0].Username.ValueString(),
-					Password:      authConfigs[0].Password.ValueString(),
-				}
-				encodedJSON, _ := json.Marshal(authConfig)
-				encodedAuth = base64.URLEncoding.EncodeToString(encodedJSON)
-			}
-		}
+	data.Sha256Digest = oldData.Sha256Digest
 
-		tflog.Debug(ctx, "Re-pushing Docker image due to trigger change", map[string]interface{}{
-			"name": imageName,
+	triggersChanged := !data.Triggers.Equal(oldData.Triggers)
+	buildChanged := !data.Build.Equal(oldData.Build)
+	if triggersChanged || buildChanged {
+		tflog.Debug(ctx, "Re-pushing Docker image due to trigger or build change", map[string]interface{}{
+			"name": data.Name.ValueString(),
 		})
 
-		pushReader, err := r.client.ImagePush(ctx, imageName, image.PushOptions{
-			RegistryAuth: encodedAuth,
-		})
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Docker Image Push Failed",
-				fmt.Sprintf("Failed to push image %s: %s", imageName, err),
-			)
+		data.Sha256Digest = r.publish(ctx, client, &data, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
 			return
 		}
-		defer pushReader.Close()
+	}
 
-		// Read push output
-		io.Copy(io.Discard, pushReader)
+	// Platforms dropped from the build leave their platform tags behind
+	if buildChanged && !data.KeepRemotely.ValueBool() && r.registryClient != nil {
+		current := platformImages(ctx, &data)
+		for _, platformImage := range platformImages(ctx, &oldData) {
+			if !slices.Contains(current, platformImage) {
+				deleteRegistryImage(ctx, r.client(&data, nil), r.hubClient, r.registries, platformImage, &resp.Diagnostics)
+			}
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -446,54 +350,429 @@ func (r *RegistryImageResource) Delete(ctx context.Context, req resource.DeleteR
 		return
 	}
 
+	imageName := data.Name.ValueString()
+
 	if data.KeepRemotely.ValueBool() {
 		tflog.Debug(ctx, "Keeping image in registry (keep_remotely=true)", map[string]interface{}{
-			"name": data.Name.ValueString(),
+			"name": imageName,
 		})
 		return
 	}
 
-	// Note: Docker API doesn't provide a direct way to delete images from registries
-	// Registry deletion would require registry-specific API calls
-	// For now, we just log a warning
-	tflog.Warn(ctx, "Registry image deletion not implemented - image remains in registry", map[string]interface{}{
-		"name": data.Name.ValueString(),
-	})
+	if r.registryClient == nil {
+		resp.Diagnostics.AddError(
+			"Unconfigured Registry Client",
+			"The provider has not been configured. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
+	registryClient := r.client(&data, nil)
 
-	resp.Diagnostics.AddWarning(
-		"Registry Deletion Not Supported",
-		fmt.Sprintf("The Docker API does not support deleting images from registries. Image %s may still exist in the registry. Use keep_remotely=true to suppress this warning.", data.Name.ValueString()),
-	)
+	deleteRegistryImage(ctx, registryClient, r.hubClient, r.registries, imageName, &resp.Diagnostics)
+
+	// The image index goes first, then the per-platform images it referenced
+	for _, platformImage := range platformImages(ctx, &data) {
+		deleteRegistryImage(ctx, registryClient, r.hubClient, r.registries, platformImage, &resp.Diagnostics)
+	}
 }
 
-func (r *RegistryImageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import by image name
-	imageName := req.ID
+// client returns the provider registry client with the given auth_config credentials layered over it,
+// skipping TLS verification when the resource asks for it
+func (r *RegistryImageResource) client(data *RegistryImageResourceModel, authConfigs []registry.AuthConfig) *distribution.Client {
+	registryClient := r.registryClient.With(authConfigs)
+	if data.InsecureSkipVerify.ValueBool() {
+		return registryClient.WithInsecureSkipVerify()
+	}
+	return registryClient
+}
+
+// platformImages returns the per-platform images publishPlatforms pushes for the build platforms of data,
+// or nothing when the build is not multi-platform
+func platformImages(ctx context.Context, data *RegistryImageResourceModel) []string {
+	var builds []RegistryBuildModel
+	if diags := data.Build.ElementsAs(ctx, &builds, false); diags.HasError() || len(builds) == 0 {
+		return nil
+	}
+
+	var platforms []string
+	if diags := builds[0].Platforms.ElementsAs(ctx, &platforms, false); diags.HasError() {
+		return nil
+	}
+
+	ref, err := distribution.ParseReference(data.Name.ValueString())
+	if err != nil || ref.Tag == "" {
+		return nil
+	}
+
+	images := make([]string, 0, len(platforms))
+	for _, platform := range platforms {
+		spec, err := distribution.ParsePlatform(platform)
+		if err != nil {
+			continue
+		}
+		images = append(images, platformReference(ref, spec).String())
+	}
+	return images
+}
 
-	inspect, _, err := r.client.ImageInspectWithRaw(ctx, imageName)
+// platformReference names the image of one platform, tagged <tag>-<os>-<arch>[-<variant>]. The daemon only
+// pushes by tag, so these tags stay visible in the repository next to the image index.
+func platformReference(ref distribution.Reference, spec ocispec.Platform) distribution.Reference {
+	return distribution.Reference{
+		Registry:   ref.Registry,
+		Repository: ref.Repository,
+		Tag:        ref.Tag + "-" + strings.ReplaceAll(distribution.FormatPlatform(spec), "/", "-"),
+	}
+}
+
+// deleteRegistryImage deletes the manifest an image name points at from its registry. Registries that
+// don't allow deletes only produce a warning, so that destroying the resource is not blocked.
+func deleteRegistryImage(ctx context.Context, registryClient *distribution.Client, hubClient *dockerhub.Client, registries *docker.AuthResolver, imageName string, diagnostics *diag.Diagnostics) {
+	ref, err := distribution.ParseReference(imageName)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Import Failed",
-			fmt.Sprintf("Failed to import registry image %s: %s", imageName, err),
+		diagnostics.AddError("Invalid Image Name", err.Error())
+		return
+	}
+
+	// Docker Hub does not accept manifest deletes over the Distribution API, so its tags are removed through the Hub API
+	if ref.Registry == "docker.io" {
+		deleteHubTag(ctx, hubClient, registries, ref, diagnostics)
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting image manifest from registry", map[string]interface{}{
+		"name": imageName,
+	})
+
+	err = registryClient.DeleteManifest(ctx, ref)
+	switch {
+	case err == nil:
+	case distribution.IsStatus(err, http.StatusNotFound):
+		tflog.Debug(ctx, "Image manifest already removed from registry", map[string]interface{}{
+			"name": imageName,
+		})
+	case distribution.IsStatus(err, http.StatusMethodNotAllowed):
+		diagnostics.AddWarning(
+			"Registry Deletion Not Supported",
+			fmt.Sprintf("The registry %s does not allow deleting manifests, so image %s remains in the registry. "+
+				"Enable deletes on the registry (REGISTRY_STORAGE_DELETE_ENABLED=true for registry:2), "+
+				"or set keep_remotely=true to suppress this warning.", ref.Registry, imageName),
+		)
+	default:
+		diagnostics.AddError(
+			"Registry Image Deletion Failed",
+			fmt.Sprintf("Unable to delete image %s from the registry: %s", imageName, err),
+		)
+	}
+}
+
+// deleteHubTag removes the tag of a Docker Hub image through the Hub API
+func deleteHubTag(ctx context.Context, hubClient *dockerhub.Client, registries *docker.AuthResolver, ref distribution.Reference, diagnostics *diag.Diagnostics) {
+	if ref.Tag == "" {
+		diagnostics.AddWarning(
+			"Registry Deletion Not Supported",
+			fmt.Sprintf("Docker Hub images can only be deleted by tag, so %s remains in the registry. Set keep_remotely=true to suppress this warning.", ref),
 		)
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), imageName)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), imageName)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("keep_remotely"), false)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("insecure_skip_verify"), false)...)
+	if hubClient == nil {
+		// Fall back to the Docker Hub credentials of registry_auth or the docker config file
+		auth, err := registries.Resolve(ref.Registry)
+		if err != nil {
+			diagnostics.AddError("Registry Auth Error", fmt.Sprintf("Failed to resolve Docker Hub credentials: %s", err))
+			return
+		}
+		if auth.Username == "" || auth.Password == "" {
+			diagnostics.AddError(
+				"Docker Hub Not Configured",
+				fmt.Sprintf("Deleting %s from Docker Hub requires hub_username with hub_password or hub_token, or Docker Hub credentials in registry_auth. "+
+					"Set keep_remotely=true to leave the image in the registry.", ref),
+			)
+			return
+		}
+
+		hubClient, err = dockerhub.NewClient(ctx, dockerhub.Config{Username: auth.Username, Password: auth.Password})
+		if err != nil {
+			diagnostics.AddError("Docker Hub Authentication Failed", fmt.Sprintf("Failed to authenticate with Docker Hub: %s", err))
+			return
+		}
+	}
+
+	namespace, repoName, _ := strings.Cut(ref.Repository, "/")
+
+	tflog.Debug(ctx, "Deleting image tag from Docker Hub", map[string]interface{}{
+		"repository": ref.Repository,
+		"tag":        ref.Tag,
+	})
+
+	if err := hubClient.DeleteRepositoryTag(ctx, namespace, repoName, ref.Tag); err != nil {
+		diagnostics.AddError(
+			"Registry Image Deletion Failed",
+			fmt.Sprintf("Unable to delete tag %s from Docker Hub repository %s: %s", ref.Tag, ref.Repository, err),
+		)
+	}
+}
+
+// authConfigs returns the credentials of the auth_config blocks
+func (r *RegistryImageResource) authConfigs(ctx context.Context, data *RegistryImageResourceModel, diagnostics *diag.Diagnostics) []registry.AuthConfig {
+	var models []RegistryAuthConfigModel
+	diagnostics.Append(data.AuthConfig.ElementsAs(ctx, &models, false)...)
+
+	authConfigs := make([]registry.AuthConfig, 0, len(models))
+	for _, model := range models {
+		authConfigs = append(authConfigs, registry.AuthConfig{
+			ServerAddress: model.Address.ValueString(),
+			Username:      model.Username.ValueString(),
+			Password:      model.Password.ValueString(),
+		})
+	}
+	return authConfigs
+}
+
+// publish builds the image when a build block is configured and pushes it, returning the digest of the
+// pushed manifest, or of the image index when the build targets several platforms
+func (r *RegistryImageResource) publish(ctx context.Context, client *docker.Client, data *RegistryImageResourceModel, diagnostics *diag.Diagnostics) tftypes.String {
+	imageName := data.Name.ValueString()
 
-	// Try to get digest
-	if len(inspect.RepoDigests) > 0 {
-		for _, rd := range inspect.RepoDigests {
-			if strings.Contains(rd, "@") {
-				parts := strings.Split(rd, "@")
-				if len(parts) == 2 {
-					resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("sha256_digest"), parts[1])...)
-					break
+	// Resolve credentials for the push, preferring the auth_config blocks
+	authConfigs := r.authConfigs(ctx, data, diagnostics)
+	if diagnostics.HasError() {
+		return tftypes.StringNull()
+	}
+
+	var builds []RegistryBuildModel
+	diagnostics.Append(data.Build.ElementsAs(ctx, &builds, false)...)
+	if diagnostics.HasError() {
+		return tftypes.StringNull()
+	}
+
+	if len(builds) == 0 {
+		return r.pushImage(ctx, client, imageName, authConfigs, diagnostics)
+	}
+	b := builds[0]
+
+	var platforms []string
+	if !b.Platforms.IsNull() {
+		diagnostics.Append(b.Platforms.ElementsAs(ctx, &platforms, false)...)
+	}
+	if len(platforms) > 0 && !b.Platform.IsNull() {
+		diagnostics.AddAttributeError(
+			path.Root("build").AtListIndex(0).AtName("platforms"),
+			"Conflicting Build Platforms",
+			"Only one of platform and platforms can be set.",
+		)
+	}
+	if diagnostics.HasError() {
+		return tftypes.StringNull()
+	}
+
+	if len(platforms) > 0 {
+		return r.publishPlatforms(ctx, client, data, b, platforms, authConfigs, diagnostics)
+	}
+
+	r.buildImage(ctx, client, imageName, b, b.Platform.ValueString(), authConfigs, diagnostics)
+	if diagnostics.HasError() {
+		return tftypes.StringNull()
+	}
+	return r.pushImage(ctx, client, imageName, authConfigs, diagnostics)
+}
+
+// publishPlatforms builds and pushes one image per platform under <tag>-<os>-<arch>[-<variant>], then
+// pushes an OCI image index referencing all of them under the image's own tag
+func (r *RegistryImageResource) publishPlatforms(ctx context.Context, client *docker.Client, data *RegistryImageResourceModel, b RegistryBuildModel, platforms []string, authConfigs []registry.AuthConfig, diagnostics *diag.Diagnostics) tftypes.String {
+	imageName := data.Name.ValueString()
+
+	ref, err := distribution.ParseReference(imageName)
+	if err != nil {
+		diagnostics.AddError("Invalid Image Name", err.Error())
+		return tftypes.StringNull()
+	}
+	if ref.Tag == "" {
+		diagnostics.AddError(
+			"Invalid Image Name",
+			fmt.Sprintf("Multi-platform image %s must be named by tag, the image index is pushed under it.", imageName),
+		)
+		return tftypes.StringNull()
+	}
+
+	registryClient := r.client(data, authConfigs)
+
+	manifests := make([]ocispec.Descriptor, 0, len(platforms))
+	for _, platform := range platforms {
+		spec, err := distribution.ParsePlatform(platform)
+		if err != nil {
+			diagnostics.AddAttributeError(path.Root("build").AtListIndex(0).AtName("platforms"), "Invalid Build Platform", err.Error())
+			return tftypes.StringNull()
+		}
+
+		platformRef := platformReference(ref, spec)
+		platformImage := platformRef.String()
+
+		r.buildImage(ctx, client, platformImage, b, platform, authConfigs, diagnostics)
+		if diagnostics.HasError() {
+			return tftypes.StringNull()
+		}
+		r.pushImage(ctx, client, platformImage, authConfigs, diagnostics)
+		if diagnostics.HasError() {
+			return tftypes.StringNull()
+		}
+
+		desc, err := registryClient.HeadManifest(ctx, platformRef)
+		if err == nil && distribution.IsIndex(desc.MediaType) {
+			// The containerd image store pushes an index even for a single platform, so pick its manifest
+			indexRef := platformRef
+			indexRef.Digest = desc.Digest.String()
+
+			var index *ocispec.Index
+			_, index, err = registryClient.GetIndex(ctx, indexRef)
+			if err == nil {
+				var ok bool
+				if desc, ok = distribution.FindPlatform(index, spec); !ok {
+					err = fmt.Errorf("image index %s has no manifest for platform %s", platformImage, platform)
 				}
 			}
 		}
+		if err != nil {
+			diagnostics.AddError(
+				"Registry Image Read Failed",
+				fmt.Sprintf("Failed to read pushed image %s from the registry: %s", platformImage, err),
+			)
+			return tftypes.StringNull()
+		}
+
+		desc.Platform = &spec
+		manifests = append(manifests, desc)
+	}
+
+	tflog.Debug(ctx, "Pushing image index to registry", map[string]interface{}{
+		"name":      imageName,
+		"platforms": platforms,
+	})
+
+	desc, err := registryClient.PutIndex(ctx, ref, manifests)
+	if err != nil {
+		diagnostics.AddError(
+			"Docker Image Push Failed",
+			fmt.Sprintf("Failed to push image index %s: %s", imageName, err),
+		)
+		return tftypes.StringNull()
+	}
+
+	return tftypes.StringValue(desc.Digest.String())
+}
+
+// pushImage pushes a local image and returns the digest of the pushed manifest as the daemon reported it
+func (r *RegistryImageResource) pushImage(ctx context.Context, client *docker.Client, imageName string, authConfigs []registry.AuthConfig, diagnostics *diag.Diagnostics) tftypes.String {
+	encodedAuth := imageAuth(r.registries, imageName, authConfigs, diagnostics)
+	if diagnostics.HasError() {
+		return tftypes.StringNull()
+	}
+
+	tflog.Debug(ctx, "Pushing Docker image to registry", map[string]interface{}{
+		"name": imageName,
+	})
+
+	pushReader, err := client.ImagePush(ctx, imageName, image.PushOptions{
+		RegistryAuth: encodedAuth,
+	})
+	if err != nil {
+		diagnostics.AddError(
+			"Docker Image Push Failed",
+			fmt.Sprintf("Failed to push image %s: %s", imageName, err),
+		)
+		return tftypes.StringNull()
+	}
+	defer pushReader.Close()
+
+	result, err := docker.ReadProgress(ctx, pushReader)
+	if err != nil {
+		diagnostics.AddError(
+			"Docker Image Push Error",
+			fmt.Sprintf("Error during push of %s: %s", imageName, err),
+		)
+		return tftypes.StringNull()
+	}
+	digest := result.Digest
+
+	tflog.Debug(ctx, "Pushed Docker image to registry", map[string]interface{}{
+		"name":   imageName,
+		"digest": digest,
+	})
+
+	if digest == "" {
+		return tftypes.StringNull()
+	}
+	return tftypes.StringValue(digest)
+}
+
+// buildImage builds the image described by the build block for a platform and tags it with imageName
+func (r *RegistryImageResource) buildImage(ctx context.Context, client *docker.Client, imageName string, b RegistryBuildModel, platform string, authConfigs []registry.AuthConfig, diagnostics *diag.Diagnostics) {
+	buildOptions := docker.BuildOptions{
+		ContextDir:  b.Context.ValueString(),
+		Dockerfile:  b.Dockerfile.ValueString(),
+		Tags:        []string{imageName},
+		Target:      b.Target.ValueString(),
+		NoCache:     b.NoCache.ValueBool(),
+		ForceRemove: b.ForceRemove.ValueBool(),
+		Platform:    platform,
+	}
+
+	if !b.BuildArgs.IsNull() {
+		buildArgs := make(map[string]string)
+		diagnostics.Append(b.BuildArgs.ElementsAs(ctx, &buildArgs, false)...)
+		buildOptions.BuildArgs = make(map[string]*string, len(buildArgs))
+		for k, v := range buildArgs {
+			buildOptions.BuildArgs[k] = &v
+		}
+	}
+
+	if !b.Labels.IsNull() {
+		labels := make(map[string]string)
+		diagnostics.Append(b.Labels.ElementsAs(ctx, &labels, false)...)
+		buildOptions.Labels = labels
+	}
+
+	if !b.CacheFrom.IsNull() {
+		var cacheFrom []string
+		diagnostics.Append(b.CacheFrom.ElementsAs(ctx, &cacheFrom, false)...)
+		buildOptions.CacheFrom = cacheFrom
+	}
+
+	// Make registry credentials available for pulling base images
+	buildOptions.AuthConfigs = buildAuthConfigs(ctx, r.registries, authConfigs, diagnostics)
+
+	if diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Building Docker image before push", map[string]interface{}{
+		"name":    imageName,
+		"context": buildOptions.ContextDir,
+	})
+
+	imageID, err := client.BuildImage(ctx, buildOptions)
+	if err != nil {
+		diagnostics.AddError(
+			"Docker Image Build Failed",
+			fmt.Sprintf("Failed to build image %s from context %s: %s", imageName, buildOptions.ContextDir, err),
+		)
+		return
 	}
+
+	tflog.Debug(ctx, "Built Docker image", map[string]interface{}{
+		"name":     imageName,
+		"image_id": imageID,
+	})
+}
+
+func (r *RegistryImageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import by image name; Read fills in the digest from the registry
+	imageName := req.ID
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), imageName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), imageName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("keep_remotely"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("insecure_skip_verify"), false)...)
 }
@@ -23,14 +23,15 @@ var (
 )
 
 type SecretResource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type SecretResourceModel struct {
-	ID     tftypes.String `tfsdk:"id"`
-	Name   tftypes.String `tfsdk:"name"`
-	Data   tftypes.String `tfsdk:"data"`
-	Labels tftypes.Map    `tfsdk:"labels"`
+	ID         tftypes.String `tfsdk:"id"`
+	Name       tftypes.String `tfsdk:"name"`
+	Data       tftypes.String `tfsdk:"data"`
+	Labels     tftypes.Map    `tfsdk:"labels"`
+	DockerHost tftypes.String `tfsdk:"docker_host"`
 }
 
 func NewSecretResource() resource.Resource {
@@ -52,6 +53,13 @@ func (r *SecretResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"docker_host": schema.StringAttribute{
+				Description: "Name of the provider hosts endpoint to manage this resource on. Defaults to the provider's own host. Changing this forces a new resource. To import onto a named host, prefix the import ID with '<host>/'.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "The name of the Docker secret.",
 				Required:    true,
@@ -90,7 +98,7 @@ func (r *SecretResource) Configure(ctx context.Context, req resource.ConfigureRe
 		return
 	}
 
-	r.client = providerData.DockerClient
+	r.clients = providerData.DockerClients
 }
 
 func (r *SecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -101,6 +109,11 @@ func (r *SecretResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Decode base64 data
 	secretData, err := base64.URLEncoding.DecodeString(data.Data.ValueString())
 	if err != nil {
@@ -136,7 +149,7 @@ func (r *SecretResource) Create(ctx context.Context, req resource.CreateRequest,
 		"name": data.Name.ValueString(),
 	})
 
-	secretResponse, err := r.client.SecretCreate(ctx, secretSpec)
+	secretResponse, err := client.SecretCreate(ctx, secretSpec)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Docker Secret Creation Failed",
@@ -163,7 +176,12 @@ func (r *SecretResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	secret, _, err := r.client.SecretInspectWithRaw(ctx, data.ID.ValueString())
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secret, _, err := client.SecretInspectWithRaw(ctx, data.ID.ValueString())
 	if err != nil {
 		if strings.Contains(err.Error(), "No such secret") || strings.Contains(err.Error(), "secret not found") {
 			tflog.Debug(ctx, "Secret not found, removing from state", map[string]interface{}{
@@ -202,8 +220,13 @@ func (r *SecretResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get current secret to get version
-	secret, _, err := r.client.SecretInspectWithRaw(ctx, data.ID.ValueString())
+	secret, _, err := client.SecretInspectWithRaw(ctx, data.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Docker Secret Update Failed",
@@ -229,7 +252,7 @@ func (r *SecretResource) Update(ctx context.Context, req resource.UpdateRequest,
 		},
 	}
 
-	err = r.client.SecretUpdate(ctx, data.ID.ValueString(), secret.Version, secretSpec)
+	err = client.SecretUpdate(ctx, data.ID.ValueString(), secret.Version, secretSpec)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Docker Secret Update Failed",
@@ -249,12 +272,17 @@ func (r *SecretResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Debug(ctx, "Deleting Docker secret", map[string]interface{}{
 		"id":   data.ID.ValueString(),
 		"name": data.Name.ValueString(),
 	})
 
-	err := r.client.SecretRemove(ctx, data.ID.ValueString())
+	err := client.SecretRemove(ctx, data.ID.ValueString())
 	if err != nil {
 		if strings.Contains(err.Error(), "No such secret") || strings.Contains(err.Error(), "secret not found") {
 			tflog.Debug(ctx, "Secret already removed", map[string]interface{}{
@@ -276,11 +304,17 @@ func (r *SecretResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 func (r *SecretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Import by ID or name
-	secret, _, err := r.client.SecretInspectWithRaw(ctx, req.ID)
+	host, id := importHostID(ctx, r.clients, req.ID, resp)
+	client := engineClient(r.clients, host, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secret, _, err := client.SecretInspectWithRaw(ctx, id)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Docker Secret Import Failed",
-			fmt.Sprintf("Failed to import secret %s: %s. Note: Secret data cannot be imported as it is never exposed after creation.", req.ID, err),
+			fmt.Sprintf("Failed to import secret %s: %s. Note: Secret data cannot be imported as it is never exposed after creation.", id, err),
 		)
 		return
 	}
@@ -2,19 +2,21 @@ package provider
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/elioseverojunior/terraform-provider-docker/internal/docker"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -34,7 +36,8 @@ var (
 )
 
 type ServiceResource struct {
-	client *docker.Client
+	clients    *docker.ClientPool
+	registries *docker.AuthResolver
 }
 
 type ServiceResourceModel struct {
@@ -49,6 +52,7 @@ type ServiceResourceModel struct {
 	RollbackConfig tftypes.List   `tfsdk:"rollback_config"`
 	ConvergeConfig tftypes.List   `tfsdk:"converge_config"`
 	Auth           tftypes.List   `tfsdk:"auth"`
+	DockerHost     tftypes.String `tfsdk:"docker_host"`
 }
 
 type TaskSpecModel struct {
@@ -84,13 +88,75 @@ type ContainerSpecModel struct {
 }
 
 type MountModel struct {
-	Target        tftypes.String `tfsdk:"target"`
-	Source        tftypes.String `tfsdk:"source"`
-	Type          tftypes.String `tfsdk:"type"`
-	ReadOnly      tftypes.Bool   `tfsdk:"read_only"`
-	BindOptions   tftypes.List   `tfsdk:"bind_options"`
-	VolumeOptions tftypes.List   `tfsdk:"volume_options"`
-	TmpfsOptions  tftypes.List   `tfsdk:"tmpfs_options"`
+	Target   tftypes.String `tfsdk:"target"`
+	Source   tftypes.String `tfsdk:"source"`
+	Type     tftypes.String `tfsdk:"type"`
+	ReadOnly tftypes.Bool   `tfsdk:"read_only"`
+}
+
+type ValueModel struct {
+	Value tftypes.String `tfsdk:"value"`
+}
+
+type ServiceHostModel struct {
+	Host tftypes.String `tfsdk:"host"`
+	IP   tftypes.String `tfsdk:"ip"`
+}
+
+type SecretRefModel struct {
+	SecretID   tftypes.String `tfsdk:"secret_id"`
+	SecretName tftypes.String `tfsdk:"secret_name"`
+	FileName   tftypes.String `tfsdk:"file_name"`
+	FileUID    tftypes.String `tfsdk:"file_uid"`
+	FileGID    tftypes.String `tfsdk:"file_gid"`
+	FileMode   tftypes.Int64  `tfsdk:"file_mode"`
+}
+
+type ConfigRefModel struct {
+	ConfigID   tftypes.String `tfsdk:"config_id"`
+	ConfigName tftypes.String `tfsdk:"config_name"`
+	FileName   tftypes.String `tfsdk:"file_name"`
+	FileUID    tftypes.String `tfsdk:"file_uid"`
+	FileGID    tftypes.String `tfsdk:"file_gid"`
+	FileMode   tftypes.Int64  `tfsdk:"file_mode"`
+}
+
+type DNSConfigModel struct {
+	Nameservers tftypes.List `tfsdk:"nameservers"`
+	Search      tftypes.List `tfsdk:"search"`
+	Options     tftypes.List `tfsdk:"options"`
+}
+
+type PrivilegesModel struct {
+	NoNewPrivileges tftypes.Bool `tfsdk:"no_new_privileges"`
+}
+
+type ResourcesModel struct {
+	Limits       tftypes.List `tfsdk:"limits"`
+	Reservations tftypes.List `tfsdk:"reservations"`
+}
+
+type ResourceLimitModel struct {
+	NanoCPUs    tftypes.Int64 `tfsdk:"nano_cpus"`
+	MemoryBytes tftypes.Int64 `tfsdk:"memory_bytes"`
+}
+
+type RestartPolicyModel struct {
+	Condition   tftypes.String `tfsdk:"condition"`
+	Delay       tftypes.String `tfsdk:"delay"`
+	MaxAttempts tftypes.Int64  `tfsdk:"max_attempts"`
+	Window      tftypes.String `tfsdk:"window"`
+}
+
+type PlacementModel struct {
+	Constraints tftypes.Set   `tfsdk:"constraints"`
+	Prefs       tftypes.Set   `tfsdk:"prefs"`
+	MaxReplicas tftypes.Int64 `tfsdk:"max_replicas"`
+}
+
+type LogDriverModel struct {
+	Name    tftypes.String `tfsdk:"name"`
+	Options tftypes.Map    `tfsdk:"options"`
 }
 
 type EndpointSpecModel struct {
@@ -126,6 +192,135 @@ type AuthModel struct {
 	Password      tftypes.String `tfsdk:"password"`
 }
 
+// Object types of the nested blocks, used to build the block lists the models are read into
+var (
+	valueObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"value": tftypes.StringType,
+	}}
+	mountObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"target":    tftypes.StringType,
+		"source":    tftypes.StringType,
+		"type":      tftypes.StringType,
+		"read_only": tftypes.BoolType,
+	}}
+	serviceHostObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"host": tftypes.StringType,
+		"ip":   tftypes.StringType,
+	}}
+	serviceHealthcheckObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"test":         tftypes.ListType{ElemType: tftypes.StringType},
+		"interval":     tftypes.StringType,
+		"timeout":      tftypes.StringType,
+		"start_period": tftypes.StringType,
+		"retries":      tftypes.Int64Type,
+	}}
+	secretRefObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"secret_id":   tftypes.StringType,
+		"secret_name": tftypes.StringType,
+		"file_name":   tftypes.StringType,
+		"file_uid":    tftypes.StringType,
+		"file_gid":    tftypes.StringType,
+		"file_mode":   tftypes.Int64Type,
+	}}
+	configRefObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"config_id":   tftypes.StringType,
+		"config_name": tftypes.StringType,
+		"file_name":   tftypes.StringType,
+		"file_uid":    tftypes.StringType,
+		"file_gid":    tftypes.StringType,
+		"file_mode":   tftypes.Int64Type,
+	}}
+	dnsConfigObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"nameservers": tftypes.ListType{ElemType: tftypes.StringType},
+		"search":      tftypes.ListType{ElemType: tftypes.StringType},
+		"options":     tftypes.ListType{ElemType: tftypes.StringType},
+	}}
+	privilegesObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"no_new_privileges": tftypes.BoolType,
+	}}
+	containerSpecObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"image":             tftypes.StringType,
+		"command":           tftypes.ListType{ElemType: valueObjectType},
+		"args":              tftypes.ListType{ElemType: valueObjectType},
+		"hostname":          tftypes.StringType,
+		"env":               tftypes.MapType{ElemType: tftypes.StringType},
+		"dir":               tftypes.StringType,
+		"user":              tftypes.StringType,
+		"groups":            tftypes.ListType{ElemType: valueObjectType},
+		"privileges":        tftypes.ListType{ElemType: privilegesObjectType},
+		"read_only":         tftypes.BoolType,
+		"mounts":            tftypes.ListType{ElemType: mountObjectType},
+		"stop_signal":       tftypes.StringType,
+		"stop_grace_period": tftypes.StringType,
+		"healthcheck":       tftypes.ListType{ElemType: serviceHealthcheckObjectType},
+		"hosts":             tftypes.ListType{ElemType: serviceHostObjectType},
+		"dns_config":        tftypes.ListType{ElemType: dnsConfigObjectType},
+		"secrets":           tftypes.ListType{ElemType: secretRefObjectType},
+		"configs":           tftypes.ListType{ElemType: configRefObjectType},
+		"labels":            tftypes.MapType{ElemType: tftypes.StringType},
+	}}
+	resourceLimitObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"nano_cpus":    tftypes.Int64Type,
+		"memory_bytes": tftypes.Int64Type,
+	}}
+	resourcesObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"limits":       tftypes.ListType{ElemType: resourceLimitObjectType},
+		"reservations": tftypes.ListType{ElemType: resourceLimitObjectType},
+	}}
+	restartPolicyObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"condition":    tftypes.StringType,
+		"delay":        tftypes.StringType,
+		"max_attempts": tftypes.Int64Type,
+		"window":       tftypes.StringType,
+	}}
+	placementObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"constraints":  tftypes.SetType{ElemType: tftypes.StringType},
+		"prefs":        tftypes.SetType{ElemType: tftypes.StringType},
+		"max_replicas": tftypes.Int64Type,
+	}}
+	logDriverObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"name":    tftypes.StringType,
+		"options": tftypes.MapType{ElemType: tftypes.StringType},
+	}}
+	taskSpecObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"container_spec": tftypes.ListType{ElemType: containerSpecObjectType},
+		"resources":      tftypes.ListType{ElemType: resourcesObjectType},
+		"restart_policy": tftypes.ListType{ElemType: restartPolicyObjectType},
+		"placement":      tftypes.ListType{ElemType: placementObjectType},
+		"networks":       tftypes.SetType{ElemType: tftypes.StringType},
+		"log_driver":     tftypes.ListType{ElemType: logDriverObjectType},
+		"force_update":   tftypes.Int64Type,
+	}}
+	portConfigObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"name":           tftypes.StringType,
+		"protocol":       tftypes.StringType,
+		"target_port":    tftypes.Int64Type,
+		"published_port": tftypes.Int64Type,
+		"publish_mode":   tftypes.StringType,
+	}}
+	endpointSpecObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"mode":  tftypes.StringType,
+		"ports": tftypes.ListType{ElemType: portConfigObjectType},
+	}}
+	updateConfigObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"parallelism":       tftypes.Int64Type,
+		"delay":             tftypes.StringType,
+		"failure_action":    tftypes.StringType,
+		"monitor":           tftypes.StringType,
+		"max_failure_ratio": tftypes.Float64Type,
+		"order":             tftypes.StringType,
+	}}
+	convergeConfigObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"delay":   tftypes.StringType,
+		"timeout": tftypes.StringType,
+	}}
+	authObjectType = tftypes.ObjectType{AttrTypes: map[string]attr.Type{
+		"server_address": tftypes.StringType,
+		"username":       tftypes.StringType,
+		"password":       tftypes.StringType,
+	}}
+)
+
 func NewServiceResource() resource.Resource {
 	return &ServiceResource{}
 }
@@ -145,6 +340,13 @@ func (r *ServiceResource) Schema(ctx context.Context, req resource.SchemaRequest
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"docker_host": schema.StringAttribute{
+				Description: "Name of the provider hosts endpoint to manage this resource on. Defaults to the provider's own host. Changing this forces a new resource. To import onto a named host, prefix the import ID with '<host>/'.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "The name of the Docker service.",
 				Required:    true,
@@ -725,6 +927,30 @@ func (r *ServiceResource) Schema(ctx context.Context, req resource.SchemaRequest
 	}
 }
 
+// encodedAuth returns the registry credentials for the service image, preferring the auth blocks
+func (r *ServiceResource) encodedAuth(ctx context.Context, data *ServiceResourceModel, serviceSpec *swarm.ServiceSpec, diagnostics *diag.Diagnostics) string {
+	var authModels []struct {
+		ServerAddress tftypes.String `tfsdk:"server_address"`
+		Username      tftypes.String `tfsdk:"username"`
+		Password      tftypes.String `tfsdk:"password"`
+	}
+	diagnostics.Append(data.Auth.ElementsAs(ctx, &authModels, false)...)
+	if diagnostics.HasError() || serviceSpec.TaskTemplate.ContainerSpec == nil {
+		return ""
+	}
+
+	authConfigs := make([]registry.AuthConfig, 0, len(authModels))
+	for _, model := range authModels {
+		authConfigs = append(authConfigs, registry.AuthConfig{
+			ServerAddress: model.ServerAddress.ValueString(),
+			Username:      model.Username.ValueString(),
+			Password:      model.Password.ValueString(),
+		})
+	}
+
+	return imageAuth(r.registries, serviceSpec.TaskTemplate.ContainerSpec.Image, authConfigs, diagnostics)
+}
+
 func (r *ServiceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -739,7 +965,8 @@ func (r *ServiceResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	r.client = providerData.DockerClient
+	r.clients = providerData.DockerClients
+	r.registries = providerData.Registries
 }
 
 func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -750,40 +977,28 @@ func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	serviceSpec, err := r.buildServiceSpec(ctx, &data, &resp.Diagnostics)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to build service spec", err.Error())
 		return
 	}
 
-	// Build auth config
-	var encodedAuth string
-	if !data.Auth.IsNull() && len(data.Auth.Elements()) > 0 {
-		var authModels []struct {
-			ServerAddress tftypes.String `tfsdk:"server_address"`
-			Username      tftypes.String `tfsdk:"username"`
-			Password      tftypes.String `tfsdk:"password"`
-		}
-		resp.Diagnostics.Append(data.Auth.ElementsAs(ctx, &authModels, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		if len(authModels) > 0 {
-			authConfig := registry.AuthConfig{
-				ServerAddress: authModels[0].ServerAddress.ValueString(),
-				Username:      authModels[0].Username.ValueString(),
-				Password:      authModels[0].Password.ValueString(),
-			}
-			encodedJSON, _ := json.Marshal(authConfig)
-			encodedAuth = base64.URLEncoding.EncodeToString(encodedJSON)
-		}
+	// Resolve credentials for the service image so that nodes can pull it
+	encodedAuth := r.encodedAuth(ctx, &data, serviceSpec, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	tflog.Debug(ctx, "Creating Docker service", map[string]interface{}{
 		"name": data.Name.ValueString(),
 	})
 
-	serviceCreateResponse, err := r.client.ServiceCreate(ctx, *serviceSpec, types.ServiceCreateOptions{
+	serviceCreateResponse, err := client.ServiceCreate(ctx, *serviceSpec, types.ServiceCreateOptions{
 		EncodedRegistryAuth: encodedAuth,
 	})
 	if err != nil {
@@ -798,7 +1013,7 @@ func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest
 
 	// Wait for convergence if configured
 	if !data.ConvergeConfig.IsNull() && len(data.ConvergeConfig.Elements()) > 0 {
-		r.waitForConvergence(ctx, data.ID.ValueString(), data.ConvergeConfig, &resp.Diagnostics)
+		r.waitForConvergence(ctx, client, data.ID.ValueString(), data.ConvergeConfig, &resp.Diagnostics)
 	}
 
 	tflog.Debug(ctx, "Created Docker service", map[string]interface{}{
@@ -817,7 +1032,12 @@ func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	service, _, err := r.client.ServiceInspectWithRaw(ctx, data.ID.ValueString(), types.ServiceInspectOptions{})
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	service, _, err := client.ServiceInspectWithRaw(ctx, data.ID.ValueString(), types.ServiceInspectOptions{})
 	if err != nil {
 		if strings.Contains(err.Error(), "No such service") || strings.Contains(err.Error(), "service not found") {
 			tflog.Debug(ctx, "Service not found, removing from state", map[string]interface{}{
@@ -833,25 +1053,9 @@ func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	data.Name = tftypes.StringValue(service.Spec.Name)
-
-	if len(service.Spec.Labels) > 0 {
-		labels, diags := tftypes.MapValueFrom(ctx, tftypes.StringType, service.Spec.Labels)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		data.Labels = labels
-	}
-
-	// Set mode
-	if service.Spec.Mode.Global != nil {
-		data.Mode = tftypes.StringValue("global")
-	} else {
-		data.Mode = tftypes.StringValue("replicated")
-		if service.Spec.Mode.Replicated != nil && service.Spec.Mode.Replicated.Replicas != nil {
-			data.Replicas = tftypes.Int64Value(int64(*service.Spec.Mode.Replicated.Replicas))
-		}
+	r.readServiceSpec(ctx, client, service.Spec, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -865,8 +1069,13 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get current service to get version
-	service, _, err := r.client.ServiceInspectWithRaw(ctx, data.ID.ValueString(), types.ServiceInspectOptions{})
+	service, _, err := client.ServiceInspectWithRaw(ctx, data.ID.ValueString(), types.ServiceInspectOptions{})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Docker Service Update Failed",
@@ -881,27 +1090,10 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	// Build auth config
-	var encodedAuth string
-	if !data.Auth.IsNull() && len(data.Auth.Elements()) > 0 {
-		var authModels []struct {
-			ServerAddress tftypes.String `tfsdk:"server_address"`
-			Username      tftypes.String `tfsdk:"username"`
-			Password      tftypes.String `tfsdk:"password"`
-		}
-		resp.Diagnostics.Append(data.Auth.ElementsAs(ctx, &authModels, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		if len(authModels) > 0 {
-			authConfig := registry.AuthConfig{
-				ServerAddress: authModels[0].ServerAddress.ValueString(),
-				Username:      authModels[0].Username.ValueString(),
-				Password:      authModels[0].Password.ValueString(),
-			}
-			encodedJSON, _ := json.Marshal(authConfig)
-			encodedAuth = base64.URLEncoding.EncodeToString(encodedJSON)
-		}
+	// Resolve credentials for the service image so that nodes can pull it
+	encodedAuth := r.encodedAuth(ctx, &data, serviceSpec, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	tflog.Debug(ctx, "Updating Docker service", map[string]interface{}{
@@ -909,7 +1101,7 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 		"name": data.Name.ValueString(),
 	})
 
-	_, err = r.client.ServiceUpdate(ctx, data.ID.ValueString(), service.Version, *serviceSpec, types.ServiceUpdateOptions{
+	_, err = client.ServiceUpdate(ctx, data.ID.ValueString(), service.Version, *serviceSpec, types.ServiceUpdateOptions{
 		EncodedRegistryAuth: encodedAuth,
 	})
 	if err != nil {
@@ -922,7 +1114,7 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	// Wait for convergence if configured
 	if !data.ConvergeConfig.IsNull() && len(data.ConvergeConfig.Elements()) > 0 {
-		r.waitForConvergence(ctx, data.ID.ValueString(), data.ConvergeConfig, &resp.Diagnostics)
+		r.waitForConvergence(ctx, client, data.ID.ValueString(), data.ConvergeConfig, &resp.Diagnostics)
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -936,12 +1128,17 @@ func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Debug(ctx, "Deleting Docker service", map[string]interface{}{
 		"id":   data.ID.ValueString(),
 		"name": data.Name.ValueString(),
 	})
 
-	err := r.client.ServiceRemove(ctx, data.ID.ValueString())
+	err := client.ServiceRemove(ctx, data.ID.ValueString())
 	if err != nil {
 		if strings.Contains(err.Error(), "No such service") || strings.Contains(err.Error(), "service not found") {
 			tflog.Debug(ctx, "Service already removed", map[string]interface{}{
@@ -962,7 +1159,8 @@ func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (r *ServiceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	_, id := importHostID(ctx, r.clients, req.ID, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
 func (r *ServiceResource) buildServiceSpec(ctx context.Context, data *ServiceResourceModel, diagnostics *diag.Diagnostics) (*swarm.ServiceSpec, error) {
@@ -1066,6 +1264,12 @@ func (r *ServiceResource) buildServiceSpec(ctx context.Context, data *ServiceRes
 						containerSpec.StopSignal = cs.StopSignal.ValueString()
 					}
 
+					if !cs.StopGracePeriod.IsNull() {
+						if gracePeriod, err := time.ParseDuration(cs.StopGracePeriod.ValueString()); err == nil {
+							containerSpec.StopGracePeriod = &gracePeriod
+						}
+					}
+
 					// Environment variables
 					if !cs.Env.IsNull() {
 						envMap := make(map[string]string)
@@ -1104,6 +1308,53 @@ func (r *ServiceResource) buildServiceSpec(ctx context.Context, data *ServiceRes
 						}
 					}
 
+					// Groups
+					if !cs.Groups.IsNull() && len(cs.Groups.Elements()) > 0 {
+						var groupItems []ValueModel
+						diagnostics.Append(cs.Groups.ElementsAs(ctx, &groupItems, false)...)
+						for _, item := range groupItems {
+							containerSpec.Groups = append(containerSpec.Groups, item.Value.ValueString())
+						}
+					}
+
+					// Extra hosts, in the "IP hostname" form used by the daemon
+					if !cs.Hosts.IsNull() && len(cs.Hosts.Elements()) > 0 {
+						var hosts []ServiceHostModel
+						diagnostics.Append(cs.Hosts.ElementsAs(ctx, &hosts, false)...)
+						for _, h := range hosts {
+							containerSpec.Hosts = append(containerSpec.Hosts, fmt.Sprintf("%s %s", h.IP.ValueString(), h.Host.ValueString()))
+						}
+					}
+
+					// DNS config
+					if !cs.DNSConfig.IsNull() && len(cs.DNSConfig.Elements()) > 0 {
+						var dnsConfigs []DNSConfigModel
+						diagnostics.Append(cs.DNSConfig.ElementsAs(ctx, &dnsConfigs, false)...)
+						if len(dnsConfigs) > 0 {
+							containerSpec.DNSConfig = &swarm.DNSConfig{}
+							if !dnsConfigs[0].Nameservers.IsNull() {
+								diagnostics.Append(dnsConfigs[0].Nameservers.ElementsAs(ctx, &containerSpec.DNSConfig.Nameservers, false)...)
+							}
+							if !dnsConfigs[0].Search.IsNull() {
+								diagnostics.Append(dnsConfigs[0].Search.ElementsAs(ctx, &containerSpec.DNSConfig.Search, false)...)
+							}
+							if !dnsConfigs[0].Options.IsNull() {
+								diagnostics.Append(dnsConfigs[0].Options.ElementsAs(ctx, &containerSpec.DNSConfig.Options, false)...)
+							}
+						}
+					}
+
+					// Privileges
+					if !cs.Privileges.IsNull() && len(cs.Privileges.Elements()) > 0 {
+						var privileges []PrivilegesModel
+						diagnostics.Append(cs.Privileges.ElementsAs(ctx, &privileges, false)...)
+						if len(privileges) > 0 {
+							containerSpec.Privileges = &swarm.Privileges{
+								NoNewPrivileges: privileges[0].NoNewPrivileges.ValueBool(),
+							}
+						}
+					}
+
 					// Mounts
 					if !cs.Mounts.IsNull() && len(cs.Mounts.Elements()) > 0 {
 						var mountConfigs []struct {
@@ -1262,6 +1513,16 @@ func (r *ServiceResource) buildServiceSpec(ctx context.Context, data *ServiceRes
 						diagnostics.Append(p.Constraints.ElementsAs(ctx, &constraints, false)...)
 						spec.TaskTemplate.Placement.Constraints = constraints
 					}
+					if !p.Prefs.IsNull() {
+						var prefs []string
+						diagnostics.Append(p.Prefs.ElementsAs(ctx, &prefs, false)...)
+						for _, pref := range prefs {
+							// Accept the docker CLI "spread=<descriptor>" form as well as a bare descriptor
+							spec.TaskTemplate.Placement.Preferences = append(spec.TaskTemplate.Placement.Preferences, swarm.PlacementPreference{
+								Spread: &swarm.SpreadOver{SpreadDescriptor: strings.TrimPrefix(pref, "spread=")},
+							})
+						}
+					}
 					if !p.MaxReplicas.IsNull() {
 						spec.TaskTemplate.Placement.MaxReplicas = uint64(p.MaxReplicas.ValueInt64())
 					}
@@ -1423,7 +1684,7 @@ func (r *ServiceResource) buildServiceSpec(ctx context.Context, data *ServiceRes
 	return spec, nil
 }
 
-func (r *ServiceResource) waitForConvergence(ctx context.Context, serviceID string, convergeConfig tftypes.List, diagnostics *diag.Diagnostics) {
+func (r *ServiceResource) waitForConvergence(ctx context.Context, client *docker.Client, serviceID string, convergeConfig tftypes.List, diagnostics *diag.Diagnostics) {
 	var configs []struct {
 		Delay   tftypes.String `tfsdk:"delay"`
 		Timeout tftypes.String `tfsdk:"timeout"`
@@ -1438,7 +1699,7 @@ func (r *ServiceResource) waitForConvergence(ctx context.Context, serviceID stri
 
 	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
-		service, _, err := r.client.ServiceInspectWithRaw(ctx, serviceID, types.ServiceInspectOptions{})
+		service, _, err := client.ServiceInspectWithRaw(ctx, serviceID, types.ServiceInspectOptions{})
 		if err != nil {
 			tflog.Warn(ctx, "Failed to inspect service during convergence", map[string]interface{}{
 				"error": err.Error(),
@@ -1471,3 +1732,438 @@ func (r *ServiceResource) waitForConvergence(ctx context.Context, serviceID stri
 		fmt.Sprintf("Service %s did not converge within the timeout period", serviceID),
 	)
 }
+
+// readServiceSpec maps the inspected service spec onto the model. Blocks the daemon reports with only default
+// values are left out unless they were already tracked in state, so refreshed plans stay clean.
+func (r *ServiceResource) readServiceSpec(ctx context.Context, client *docker.Client, spec swarm.ServiceSpec, data *ServiceResourceModel, diagnostics *diag.Diagnostics) {
+	data.Name = tftypes.StringValue(spec.Name)
+	data.Labels = stringMapOrNull(ctx, spec.Labels, diagnostics)
+
+	// Set mode
+	if spec.Mode.Global != nil {
+		data.Mode = tftypes.StringValue("global")
+		if data.Replicas.IsNull() {
+			data.Replicas = tftypes.Int64Value(1)
+		}
+	} else {
+		data.Mode = tftypes.StringValue("replicated")
+		if spec.Mode.Replicated != nil && spec.Mode.Replicated.Replicas != nil {
+			data.Replicas = tftypes.Int64Value(int64(*spec.Mode.Replicated.Replicas))
+		}
+	}
+
+	// Task spec
+	priorTask := firstBlock[TaskSpecModel](ctx, data.TaskSpec, diagnostics)
+	if diagnostics.HasError() {
+		return
+	}
+
+	taskTemplate := spec.TaskTemplate
+	task := TaskSpecModel{
+		ContainerSpec: readServiceContainerSpec(ctx, taskTemplate.ContainerSpec, priorTask.ContainerSpec, diagnostics),
+		Resources:     readServiceResources(ctx, taskTemplate.Resources, priorTask.Resources, diagnostics),
+		RestartPolicy: readServiceRestartPolicy(ctx, taskTemplate.RestartPolicy, priorTask.RestartPolicy, diagnostics),
+		Placement:     readServicePlacement(ctx, taskTemplate.Placement, priorTask.Placement, diagnostics),
+		Networks:      r.readServiceNetworks(ctx, client, taskTemplate.Networks, priorTask.Networks, diagnostics),
+		LogDriver:     readServiceLogDriver(ctx, taskTemplate.LogDriver, diagnostics),
+		ForceUpdate:   tftypes.Int64Value(int64(taskTemplate.ForceUpdate)),
+	}
+	data.TaskSpec = blockList(ctx, taskSpecObjectType, []TaskSpecModel{task}, diagnostics)
+
+	data.EndpointSpec = readServiceEndpointSpec(ctx, spec.EndpointSpec, data.EndpointSpec, diagnostics)
+	data.UpdateConfig = readServiceUpdateConfig(ctx, spec.UpdateConfig, data.UpdateConfig, diagnostics)
+	data.RollbackConfig = readServiceUpdateConfig(ctx, spec.RollbackConfig, data.RollbackConfig, diagnostics)
+
+	// converge_config only affects how the provider waits, and the daemon never returns registry
+	// credentials, so both keep their values from state
+	if data.ConvergeConfig.IsNull() {
+		data.ConvergeConfig = blockList[ConvergeConfigModel](ctx, convergeConfigObjectType, nil, diagnostics)
+	}
+	if data.Auth.IsNull() {
+		data.Auth = blockList[AuthModel](ctx, authObjectType, nil, diagnostics)
+	}
+}
+
+func readServiceContainerSpec(ctx context.Context, cs *swarm.ContainerSpec, priorList tftypes.List, diagnostics *diag.Diagnostics) tftypes.List {
+	if cs == nil {
+		return blockList[ContainerSpecModel](ctx, containerSpecObjectType, nil, diagnostics)
+	}
+
+	prior := firstBlock[ContainerSpecModel](ctx, priorList, diagnostics)
+
+	// The docker CLI pins images to a digest; keep the configured reference when it matches
+	image := cs.Image
+	if priorImage := prior.Image.ValueString(); priorImage != "" && !strings.Contains(priorImage, "@") {
+		if name, _, found := strings.Cut(image, "@"); found && name == priorImage {
+			image = priorImage
+		}
+	}
+
+	model := ContainerSpecModel{
+		Image:           tftypes.StringValue(image),
+		Hostname:        stringOrNull(cs.Hostname),
+		Env:             stringMapOrNull(ctx, envToMap(cs.Env), diagnostics),
+		Dir:             stringOrNull(cs.Dir),
+		User:            stringOrNull(cs.User),
+		ReadOnly:        tftypes.BoolValue(cs.ReadOnly),
+		StopSignal:      stringOrNull(cs.StopSignal),
+		StopGracePeriod: tftypes.StringNull(),
+		Labels:          stringMapOrNull(ctx, cs.Labels, diagnostics),
+	}
+	if cs.StopGracePeriod != nil {
+		model.StopGracePeriod = formatDuration(*cs.StopGracePeriod, prior.StopGracePeriod)
+	}
+
+	model.Command = blockList(ctx, valueObjectType, valueModels(cs.Command), diagnostics)
+	model.Args = blockList(ctx, valueObjectType, valueModels(cs.Args), diagnostics)
+	model.Groups = blockList(ctx, valueObjectType, valueModels(cs.Groups), diagnostics)
+
+	// Mounts
+	var mounts []MountModel
+	for _, m := range cs.Mounts {
+		mounts = append(mounts, MountModel{
+			Target:   tftypes.StringValue(m.Target),
+			Source:   stringOrNull(m.Source),
+			Type:     tftypes.StringValue(string(m.Type)),
+			ReadOnly: tftypes.BoolValue(m.ReadOnly),
+		})
+	}
+	model.Mounts = blockList(ctx, mountObjectType, mounts, diagnostics)
+
+	// Extra hosts are stored as "IP hostname [aliases...]"
+	var hosts []ServiceHostModel
+	for _, entry := range cs.Hosts {
+		fields := strings.Fields(entry)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, host := range fields[1:] {
+			hosts = append(hosts, ServiceHostModel{
+				Host: tftypes.StringValue(host),
+				IP:   tftypes.StringValue(fields[0]),
+			})
+		}
+	}
+	model.Hosts = blockList(ctx, serviceHostObjectType, hosts, diagnostics)
+
+	// Healthcheck
+	var healthchecks []HealthcheckModel
+	priorHealthcheck := firstBlock[HealthcheckModel](ctx, prior.Healthcheck, diagnostics)
+	if healthcheck := mapHealthcheck(ctx, cs.Healthcheck, nil, &priorHealthcheck, diagnostics); healthcheck != nil {
+		healthchecks = append(healthchecks, *healthcheck)
+	}
+	model.Healthcheck = blockList(ctx, serviceHealthcheckObjectType, healthchecks, diagnostics)
+
+	// Secrets and configs. References without a file target are not managed by this resource.
+	var secrets []SecretRefModel
+	for _, ref := range cs.Secrets {
+		if ref == nil || ref.File == nil {
+			continue
+		}
+		secrets = append(secrets, SecretRefModel{
+			SecretID:   tftypes.StringValue(ref.SecretID),
+			SecretName: tftypes.StringValue(ref.SecretName),
+			FileName:   tftypes.StringValue(ref.File.Name),
+			FileUID:    tftypes.StringValue(ref.File.UID),
+			FileGID:    tftypes.StringValue(ref.File.GID),
+			FileMode:   tftypes.Int64Value(int64(ref.File.Mode)),
+		})
+	}
+	model.Secrets = blockList(ctx, secretRefObjectType, secrets, diagnostics)
+
+	var configs []ConfigRefModel
+	for _, ref := range cs.Configs {
+		if ref == nil || ref.File == nil {
+			continue
+		}
+		configs = append(configs, ConfigRefModel{
+			ConfigID:   tftypes.StringValue(ref.ConfigID),
+			ConfigName: tftypes.StringValue(ref.ConfigName),
+			FileName:   tftypes.StringValue(ref.File.Name),
+			FileUID:    tftypes.StringValue(ref.File.UID),
+			FileGID:    tftypes.StringValue(ref.File.GID),
+			FileMode:   tftypes.Int64Value(int64(ref.File.Mode)),
+		})
+	}
+	model.Configs = blockList(ctx, configRefObjectType, configs, diagnostics)
+
+	// DNS config
+	var dnsConfigs []DNSConfigModel
+	if dns := cs.DNSConfig; dns != nil && (len(dns.Nameservers) > 0 || len(dns.Search) > 0 || len(dns.Options) > 0 || len(prior.DNSConfig.Elements()) > 0) {
+		dnsConfigs = append(dnsConfigs, DNSConfigModel{
+			Nameservers: stringListOrNull(ctx, dns.Nameservers, diagnostics),
+			Search:      stringListOrNull(ctx, dns.Search, diagnostics),
+			Options:     stringListOrNull(ctx, dns.Options, diagnostics),
+		})
+	}
+	model.DNSConfig = blockList(ctx, dnsConfigObjectType, dnsConfigs, diagnostics)
+
+	// Privileges
+	var privileges []PrivilegesModel
+	priorPrivileges := firstBlock[PrivilegesModel](ctx, prior.Privileges, diagnostics)
+	if cs.Privileges != nil && (cs.Privileges.NoNewPrivileges || len(prior.Privileges.Elements()) > 0) {
+		noNewPrivileges := tftypes.BoolValue(cs.Privileges.NoNewPrivileges)
+		if !cs.Privileges.NoNewPrivileges && priorPrivileges.NoNewPrivileges.IsNull() {
+			noNewPrivileges = tftypes.BoolNull()
+		}
+		privileges = append(privileges, PrivilegesModel{NoNewPrivileges: noNewPrivileges})
+	}
+	model.Privileges = blockList(ctx, privilegesObjectType, privileges, diagnostics)
+
+	return blockList(ctx, containerSpecObjectType, []ContainerSpecModel{model}, diagnostics)
+}
+
+func readServiceResources(ctx context.Context, res *swarm.ResourceRequirements, priorList tftypes.List, diagnostics *diag.Diagnostics) tftypes.List {
+	var limits, reservations []ResourceLimitModel
+	if res != nil && res.Limits != nil && (res.Limits.NanoCPUs != 0 || res.Limits.MemoryBytes != 0) {
+		limits = append(limits, ResourceLimitModel{
+			NanoCPUs:    int64OrNull(res.Limits.NanoCPUs),
+			MemoryBytes: int64OrNull(res.Limits.MemoryBytes),
+		})
+	}
+	if res != nil && res.Reservations != nil && (res.Reservations.NanoCPUs != 0 || res.Reservations.MemoryBytes != 0) {
+		reservations = append(reservations, ResourceLimitModel{
+			NanoCPUs:    int64OrNull(res.Reservations.NanoCPUs),
+			MemoryBytes: int64OrNull(res.Reservations.MemoryBytes),
+		})
+	}
+
+	var resources []ResourcesModel
+	if len(limits) > 0 || len(reservations) > 0 || len(priorList.Elements()) > 0 {
+		resources = append(resources, ResourcesModel{
+			Limits:       blockList(ctx, resourceLimitObjectType, limits, diagnostics),
+			Reservations: blockList(ctx, resourceLimitObjectType, reservations, diagnostics),
+		})
+	}
+	return blockList(ctx, resourcesObjectType, resources, diagnostics)
+}
+
+func readServiceRestartPolicy(ctx context.Context, rp *swarm.RestartPolicy, priorList tftypes.List, diagnostics *diag.Diagnostics) tftypes.List {
+	if rp == nil {
+		return blockList[RestartPolicyModel](ctx, restartPolicyObjectType, nil, diagnostics)
+	}
+
+	prior := firstBlock[RestartPolicyModel](ctx, priorList, diagnostics)
+	model := RestartPolicyModel{
+		Condition:   tftypes.StringValue(string(rp.Condition)),
+		Delay:       tftypes.StringValue("5s"),
+		MaxAttempts: tftypes.Int64Null(),
+		Window:      tftypes.StringNull(),
+	}
+	if rp.Condition == "" {
+		model.Condition = tftypes.StringValue(string(swarm.RestartPolicyConditionAny))
+	}
+	if rp.Delay != nil {
+		model.Delay = formatDuration(*rp.Delay, prior.Delay)
+	}
+	if rp.MaxAttempts != nil {
+		model.MaxAttempts = tftypes.Int64Value(int64(*rp.MaxAttempts))
+	}
+	if rp.Window != nil {
+		model.Window = formatDuration(*rp.Window, prior.Window)
+	}
+	return blockList(ctx, restartPolicyObjectType, []RestartPolicyModel{model}, diagnostics)
+}
+
+func readServicePlacement(ctx context.Context, placement *swarm.Placement, priorList tftypes.List, diagnostics *diag.Diagnostics) tftypes.List {
+	var placements []PlacementModel
+	if placement != nil && (len(placement.Constraints) > 0 || len(placement.Preferences) > 0 || placement.MaxReplicas > 0 || len(priorList.Elements()) > 0) {
+		prior := firstBlock[PlacementModel](ctx, priorList, diagnostics)
+		var priorPrefs []string
+		if !prior.Prefs.IsNull() {
+			diagnostics.Append(prior.Prefs.ElementsAs(ctx, &priorPrefs, false)...)
+		}
+
+		// Preferences are reported in the "spread=<descriptor>" form unless configured bare
+		var prefs []string
+		for _, pref := range placement.Preferences {
+			if pref.Spread == nil {
+				continue
+			}
+			if slices.Contains(priorPrefs, pref.Spread.SpreadDescriptor) {
+				prefs = append(prefs, pref.Spread.SpreadDescriptor)
+			} else {
+				prefs = append(prefs, "spread="+pref.Spread.SpreadDescriptor)
+			}
+		}
+
+		model := PlacementModel{
+			Constraints: stringSetOrNull(ctx, placement.Constraints, diagnostics),
+			Prefs:       stringSetOrNull(ctx, prefs, diagnostics),
+			MaxReplicas: int64OrNull(int64(placement.MaxReplicas)),
+		}
+		if placement.MaxReplicas == 0 && !prior.MaxReplicas.IsNull() {
+			model.MaxReplicas = tftypes.Int64Value(0)
+		}
+		placements = append(placements, model)
+	}
+	return blockList(ctx, placementObjectType, placements, diagnostics)
+}
+
+func readServiceLogDriver(ctx context.Context, driver *swarm.Driver, diagnostics *diag.Diagnostics) tftypes.List {
+	var logDrivers []LogDriverModel
+	if driver != nil && driver.Name != "" {
+		logDrivers = append(logDrivers, LogDriverModel{
+			Name:    tftypes.StringValue(driver.Name),
+			Options: stringMapOrNull(ctx, driver.Options, diagnostics),
+		})
+	}
+	return blockList(ctx, logDriverObjectType, logDrivers, diagnostics)
+}
+
+// readServiceNetworks reports attached networks by name. The daemon stores network IDs, so an ID is only kept
+// when it was configured that way, and the names of the other networks are looked up in a single list call.
+func (r *ServiceResource) readServiceNetworks(ctx context.Context, client *docker.Client, attachments []swarm.NetworkAttachmentConfig, prior tftypes.Set, diagnostics *diag.Diagnostics) tftypes.Set {
+	var priorNetworks []string
+	if !prior.IsNull() && !prior.IsUnknown() {
+		diagnostics.Append(prior.ElementsAs(ctx, &priorNetworks, false)...)
+	}
+
+	var lookup []filters.KeyValuePair
+	for _, attachment := range attachments {
+		if !slices.Contains(priorNetworks, attachment.Target) {
+			lookup = append(lookup, filters.Arg("id", attachment.Target))
+		}
+	}
+
+	names := make(map[string]string)
+	if len(lookup) > 0 {
+		networks, err := client.NetworkList(ctx, network.ListOptions{Filters: filters.NewArgs(lookup...)})
+		if err == nil {
+			for _, nw := range networks {
+				names[nw.ID] = nw.Name
+			}
+		}
+	}
+
+	var networks []string
+	for _, attachment := range attachments {
+		target := attachment.Target
+		if name, ok := names[target]; ok {
+			target = name
+		}
+		networks = append(networks, target)
+	}
+	return stringSetOrNull(ctx, networks, diagnostics)
+}
+
+func readServiceEndpointSpec(ctx context.Context, es *swarm.EndpointSpec, priorList tftypes.List, diagnostics *diag.Diagnostics) tftypes.List {
+	var endpointSpecs []EndpointSpecModel
+	if es != nil && ((es.Mode != "" && es.Mode != swarm.ResolutionModeVIP) || len(es.Ports) > 0 || len(priorList.Elements()) > 0) {
+		var ports []PortConfigModel
+		for _, port := range es.Ports {
+			model := PortConfigModel{
+				Name:          stringOrNull(port.Name),
+				Protocol:      tftypes.StringValue(string(port.Protocol)),
+				TargetPort:    tftypes.Int64Value(int64(port.TargetPort)),
+				PublishedPort: int64OrNull(int64(port.PublishedPort)),
+				PublishMode:   tftypes.StringValue(string(port.PublishMode)),
+			}
+			if port.Protocol == "" {
+				model.Protocol = tftypes.StringValue(string(swarm.PortConfigProtocolTCP))
+			}
+			if port.PublishMode == "" {
+				model.PublishMode = tftypes.StringValue(string(swarm.PortConfigPublishModeIngress))
+			}
+			ports = append(ports, model)
+		}
+
+		mode := es.Mode
+		if mode == "" {
+			mode = swarm.ResolutionModeVIP
+		}
+		endpointSpecs = append(endpointSpecs, EndpointSpecModel{
+			Mode:  tftypes.StringValue(string(mode)),
+			Ports: blockList(ctx, portConfigObjectType, ports, diagnostics),
+		})
+	}
+	return blockList(ctx, endpointSpecObjectType, endpointSpecs, diagnostics)
+}
+
+// readServiceUpdateConfig maps an update or rollback config, which share the same block layout
+func readServiceUpdateConfig(ctx context.Context, uc *swarm.UpdateConfig, priorList tftypes.List, diagnostics *diag.Diagnostics) tftypes.List {
+	if uc == nil {
+		return blockList[UpdateConfigModel](ctx, updateConfigObjectType, nil, diagnostics)
+	}
+
+	prior := firstBlock[UpdateConfigModel](ctx, priorList, diagnostics)
+	model := UpdateConfigModel{
+		Parallelism:     tftypes.Int64Value(int64(uc.Parallelism)),
+		Delay:           formatDuration(uc.Delay, prior.Delay),
+		FailureAction:   tftypes.StringValue(uc.FailureAction),
+		Monitor:         formatDuration(uc.Monitor, prior.Monitor),
+		MaxFailureRatio: tftypes.Float64Null(),
+		Order:           tftypes.StringValue(uc.Order),
+	}
+	if uc.FailureAction == "" {
+		model.FailureAction = tftypes.StringValue(swarm.UpdateFailureActionPause)
+	}
+	if uc.Order == "" {
+		model.Order = tftypes.StringValue(swarm.UpdateOrderStopFirst)
+	}
+
+	// The ratio is stored as a float32; keep the configured value when it rounds to the same number
+	switch {
+	case !prior.MaxFailureRatio.IsNull() && float32(prior.MaxFailureRatio.ValueFloat64()) == uc.MaxFailureRatio:
+		model.MaxFailureRatio = prior.MaxFailureRatio
+	case uc.MaxFailureRatio != 0:
+		model.MaxFailureRatio = tftypes.Float64Value(float64(uc.MaxFailureRatio))
+	}
+
+	return blockList(ctx, updateConfigObjectType, []UpdateConfigModel{model}, diagnostics)
+}
+
+// firstBlock decodes the first element of a list block, returning the zero model when the block is absent
+func firstBlock[T any](ctx context.Context, list tftypes.List, diagnostics *diag.Diagnostics) T {
+	var items []T
+	if !list.IsNull() && !list.IsUnknown() {
+		diagnostics.Append(list.ElementsAs(ctx, &items, false)...)
+	}
+
+	var zero T
+	if len(items) == 0 {
+		return zero
+	}
+	return items[0]
+}
+
+// blockList converts block models into a list block value. Absent blocks are an empty list, as in configuration.
+func blockList[T any](ctx context.Context, elemType attr.Type, items []T, diagnostics *diag.Diagnostics) tftypes.List {
+	if items == nil {
+		items = []T{}
+	}
+	list, diags := tftypes.ListValueFrom(ctx, elemType, items)
+	diagnostics.Append(diags...)
+	return list
+}
+
+func valueModels(values []string) []ValueModel {
+	var models []ValueModel
+	for _, v := range values {
+		models = append(models, ValueModel{Value: tftypes.StringValue(v)})
+	}
+	return models
+}
+
+func stringSetOrNull(ctx context.Context, values []string, diagnostics *diag.Diagnostics) tftypes.Set {
+	if len(values) == 0 {
+		return tftypes.SetNull(tftypes.StringType)
+	}
+	set, diags := tftypes.SetValueFrom(ctx, tftypes.StringType, values)
+	diagnostics.Append(diags...)
+	return set
+}
+
+func stringOrNull(s string) tftypes.String {
+	if s == "" {
+		return tftypes.StringNull()
+	}
+	return tftypes.StringValue(s)
+}
+
+func int64OrNull(v int64) tftypes.Int64 {
+	if v == 0 {
+		return tftypes.Int64Null()
+	}
+	return tftypes.Int64Value(v)
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// TestServiceObjectTypesMatchSchema keeps the object types Read builds block lists with in line with the schema
+func TestServiceObjectTypesMatchSchema(t *testing.T) {
+	ctx := context.Background()
+
+	var resp resource.SchemaResponse
+	(&ServiceResource{}).Schema(ctx, resource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Schema returned %v", resp.Diagnostics)
+	}
+
+	blocks := map[string]attr.Type{
+		"task_spec":       taskSpecObjectType,
+		"endpoint_spec":   endpointSpecObjectType,
+		"update_config":   updateConfigObjectType,
+		"rollback_config": updateConfigObjectType,
+		"converge_config": convergeConfigObjectType,
+		"auth":            authObjectType,
+	}
+	for name, want := range blocks {
+		schemaType, diags := resp.Schema.TypeAtPath(ctx, path.Root(name))
+		if diags.HasError() {
+			t.Fatalf("TypeAtPath(%s) returned %v", name, diags)
+		}
+
+		listType, ok := schemaType.(attr.TypeWithElementType)
+		if !ok {
+			t.Fatalf("block %s has type %T, want a list", name, schemaType)
+		}
+		if got := listType.ElementType(); !got.Equal(want) {
+			t.Errorf("block %s has element type\n%s\nwant\n%s", name, got, want)
+		}
+	}
+}
@@ -22,7 +22,7 @@ var (
 )
 
 type TagResource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type TagResourceModel struct {
@@ -30,6 +30,7 @@ type TagResourceModel struct {
 	SourceImage   tftypes.String `tfsdk:"source_image"`
 	TargetImage   tftypes.String `tfsdk:"target_image"`
 	SourceImageID tftypes.String `tfsdk:"source_image_id"`
+	DockerHost    tftypes.String `tfsdk:"docker_host"`
 }
 
 func NewTagResource() resource.Resource {
@@ -51,6 +52,13 @@ func (r *TagResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"docker_host": schema.StringAttribute{
+				Description: "Name of the provider hosts endpoint to manage this resource on. Defaults to the provider's own host. Changing this forces a new resource. To import onto a named host, prefix the import ID with '<host>/'.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"source_image": schema.StringAttribute{
 				Description: "The source Docker image name with tag (e.g., 'nginx:latest' or 'myregistry/myimage:v1.0').",
 				Required:    true,
@@ -87,7 +95,7 @@ func (r *TagResource) Configure(ctx context.Context, req resource.ConfigureReque
 		return
 	}
 
-	r.client = providerData.DockerClient
+	r.clients = providerData.DockerClients
 }
 
 func (r *TagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -98,11 +106,16 @@ func (r *TagResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	sourceImage := data.SourceImage.ValueString()
 	targetImage := data.TargetImage.ValueString()
 
 	// Inspect source image to get its ID
-	srcInspect, _, err := r.client.ImageInspectWithRaw(ctx, sourceImage)
+	srcInspect, _, err := client.ImageInspectWithRaw(ctx, sourceImage)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Source Image Not Found",
@@ -117,7 +130,7 @@ func (r *TagResource) Create(ctx context.Context, req resource.CreateRequest, re
 	})
 
 	// Create the tag
-	err = r.client.ImageTag(ctx, sourceImage, targetImage)
+	err = client.ImageTag(ctx, sourceImage, targetImage)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Docker Tag Failed",
@@ -145,10 +158,15 @@ func (r *TagResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	targetImage := data.TargetImage.ValueString()
 
 	// Check if the target image exists
-	inspect, _, err := r.client.ImageInspectWithRaw(ctx, targetImage)
+	inspect, _, err := client.ImageInspectWithRaw(ctx, targetImage)
 	if err != nil {
 		if strings.Contains(err.Error(), "No such image") {
 			tflog.Debug(ctx, "Image tag not found, removing from state", map[string]interface{}{
@@ -178,6 +196,7 @@ func (r *TagResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -189,6 +208,11 @@ func (r *TagResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	targetImage := data.TargetImage.ValueString()
 
 	tflog.Debug(ctx, "Removing Docker image tag", map[string]interface{}{
@@ -197,7 +221,7 @@ func (r *TagResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 
 	// Remove the tag (not the underlying image)
 	// We use ImageRemove with NoPrune to only remove the tag, not the image layers
-	_, err := r.client.ImageRemove(ctx, targetImage, image.RemoveOptions{
+	_, err := client.ImageRemove(ctx, targetImage, image.RemoveOptions{
 		Force:         false,
 		PruneChildren: false,
 	})
@@ -222,9 +246,14 @@ func (r *TagResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 
 func (r *TagResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Import by target image name
-	targetImage := req.ID
+	host, targetImage := importHostID(ctx, r.clients, req.ID, resp)
+
+	client := engineClient(r.clients, host, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	inspect, _, err := r.client.ImageInspectWithRaw(ctx, targetImage)
+	inspect, _, err := client.ImageInspectWithRaw(ctx, targetImage)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Import Failed",
@@ -24,7 +24,7 @@ var (
 )
 
 type VolumeResource struct {
-	client *docker.Client
+	clients *docker.ClientPool
 }
 
 type VolumeResourceModel struct {
@@ -35,6 +35,7 @@ type VolumeResourceModel struct {
 	Labels     types.Map    `tfsdk:"labels"`
 	Mountpoint types.String `tfsdk:"mountpoint"`
 	Force      types.Bool   `tfsdk:"force"`
+	DockerHost types.String `tfsdk:"docker_host"`
 }
 
 func NewVolumeResource() resource.Resource {
@@ -56,6 +57,13 @@ func (r *VolumeResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"docker_host": schema.StringAttribute{
+				Description: "Name of the provider hosts endpoint to manage this resource on. Defaults to the provider's own host. Changing this forces a new resource. To import onto a named host, prefix the import ID with '<host>/'.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "The name of the Docker volume.",
 				Required:    true,
@@ -110,7 +118,7 @@ func (r *VolumeResource) Configure(ctx context.Context, req resource.ConfigureRe
 		return
 	}
 
-	r.client = providerData.DockerClient
+	r.clients = providerData.DockerClients
 }
 
 func (r *VolumeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -121,6 +129,11 @@ func (r *VolumeResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	volumeName := data.Name.ValueString()
 	tflog.Debug(ctx, "Creating Docker volume", map[string]interface{}{
 		"name": volumeName,
@@ -151,7 +164,7 @@ func (r *VolumeResource) Create(ctx context.Context, req resource.CreateRequest,
 		createOptions.Labels = labels
 	}
 
-	volumeResp, err := r.client.VolumeCreate(ctx, createOptions)
+	volumeResp, err := client.VolumeCreate(ctx, createOptions)
 	if err != nil {
 		resp.Diagnostics.AddError("Volume Create Error", fmt.Sprintf("Unable to create volume %s: %s", volumeName, err))
 		return
@@ -176,9 +189,14 @@ func (r *VolumeResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	volumeName := data.Name.ValueString()
 
-	volumeInspect, err := r.client.VolumeInspect(ctx, volumeName)
+	volumeInspect, err := client.VolumeInspect(ctx, volumeName)
 	if err != nil {
 		if strings.Contains(err.Error(), "no such volume") || strings.Contains(err.Error(), "not found") {
 			resp.State.RemoveResource(ctx)
@@ -232,13 +250,18 @@ func (r *VolumeResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	client := engineClient(r.clients, data.DockerHost.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	volumeName := data.Name.ValueString()
 
 	tflog.Debug(ctx, "Deleting Docker volume", map[string]interface{}{
 		"name": volumeName,
 	})
 
-	err := r.client.VolumeRemove(ctx, volumeName, data.Force.ValueBool())
+	err := client.VolumeRemove(ctx, volumeName, data.Force.ValueBool())
 	if err != nil {
 		if strings.Contains(err.Error(), "no such volume") || strings.Contains(err.Error(), "not found") {
 			return
@@ -253,5 +276,6 @@ func (r *VolumeResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (r *VolumeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	_, id := importHostID(ctx, r.clients, req.ID, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), id)...)
 }